@@ -8,6 +8,7 @@ import (
 	"github.com/caarlos0/ctrlc"
 	"github.com/fatih/color"
 	"github.com/goreleaser/goreleaser/internal/middleware"
+	"github.com/goreleaser/goreleaser/internal/pipe/announce"
 	"github.com/goreleaser/goreleaser/internal/pipe/git"
 	"github.com/goreleaser/goreleaser/internal/pipeline"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -106,6 +107,9 @@ func releaseProject(options releaseOpts) (*context.Context, error) {
 				middleware.ErrHandler(pipe.Run),
 				middleware.DefaultInitialPadding,
 			)(ctx); err != nil {
+				if aerr := announce.Failed(ctx, pipe.String(), err); aerr != nil {
+					log.WithError(aerr).Warn("failed to announce the release failure")
+				}
 				return err
 			}
 		}
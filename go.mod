@@ -3,9 +3,12 @@ module github.com/goreleaser/goreleaser
 go 1.16
 
 require (
+	cloud.google.com/go/storage v1.15.0
 	code.gitea.io/sdk/gitea v0.14.1
 	github.com/Masterminds/semver/v3 v3.1.1
+	github.com/ProtonMail/go-crypto v0.0.0-20210512092938-c05353c2d58c
 	github.com/apex/log v1.9.0
+	github.com/aws/aws-sdk-go v1.38.35
 	github.com/caarlos0/ctrlc v1.0.0
 	github.com/caarlos0/env/v6 v6.6.2
 	github.com/caarlos0/go-shellwords v1.0.12
@@ -14,11 +17,13 @@ require (
 	github.com/dghubble/oauth1 v0.7.0
 	github.com/fatih/color v1.12.0
 	github.com/google/go-github/v35 v35.3.0
+	github.com/goreleaser/chglog v0.1.2
 	github.com/goreleaser/fileglob v1.2.0
 	github.com/goreleaser/nfpm/v2 v2.6.0
 	github.com/imdario/mergo v0.3.12
 	github.com/jarcoal/httpmock v1.0.8
 	github.com/mitchellh/go-homedir v1.1.0
+	github.com/sassoftware/go-rpmutils v0.0.0-20190420191620-a8f1baeba37b
 	github.com/spf13/cobra v1.2.1
 	github.com/stretchr/testify v1.7.0
 	github.com/ulikunitz/xz v0.5.10
@@ -0,0 +1,62 @@
+// Package announcer contains the helpers shared by the announce pipes.
+package announcer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// DefaultMessageTemplate is the default template of the plain text
+// announcements.
+const DefaultMessageTemplate = `{{ .ProjectName }} {{ .Tag }} is out! Check it out at {{ .GitURL }}/releases/tag/{{ .Tag }}`
+
+// PostJSON posts the given payload as JSON to the given url, failing if the
+// response status code is not 2xx.
+func PostJSON(ctx *context.Context, url string, payload interface{}, headers map[string]string) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return Send(ctx, http.MethodPost, url, "application/json", body, headers)
+}
+
+// Send sends the given body to the given url, failing if the response status
+// code is not 2xx. The url is never part of the returned errors, as it often
+// contains secrets, e.g. webhook tokens.
+func Send(ctx *context.Context, method, url, contentType string, body []byte, headers map[string]string) error {
+	err := send(ctx, method, url, contentType, body, headers)
+	var uerr *neturl.Error
+	if errors.As(err, &uerr) {
+		return uerr.Err
+	}
+	return err
+}
+
+func send(ctx *context.Context, method, url, contentType string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		bts, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected http status code %d: %s", resp.StatusCode, strings.TrimSpace(string(bts)))
+	}
+	return nil
+}
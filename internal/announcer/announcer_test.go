@@ -0,0 +1,79 @@
+package announcer
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostJSON(t *testing.T) {
+	var contentType, auth string
+	var body map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		contentType = r.Header.Get("Content-Type")
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{})
+	require.NoError(t, PostJSON(ctx, srv.URL, map[string]string{"text": "hi"}, map[string]string{
+		"Authorization": "Bearer token",
+	}))
+	require.Equal(t, "application/json", contentType)
+	require.Equal(t, "Bearer token", auth)
+	require.Equal(t, map[string]string{"text": "hi"}, body)
+}
+
+func TestPostJSONError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, "invalid payload")
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{})
+	require.EqualError(t, PostJSON(ctx, srv.URL, nil, nil), "unexpected http status code 400: invalid payload")
+}
+
+func TestPostJSONInvalidPayload(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.Error(t, PostJSON(ctx, "http://localhost", func() {}, nil))
+}
+
+func TestPostJSONHidesURL(t *testing.T) {
+	ctx := context.New(config.Project{})
+	for _, url := range []string{
+		"http://localhost:1/webhooks/123/s3cr3t",
+		"http://local\x7fhost/webhooks/123/s3cr3t",
+	} {
+		err := PostJSON(ctx, url, map[string]string{"text": "hi"}, nil)
+		require.Error(t, err)
+		require.NotContains(t, err.Error(), "s3cr3t")
+	}
+}
+
+func TestSend(t *testing.T) {
+	var method, contentType, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		contentType = r.Header.Get("Content-Type")
+		bts, _ := io.ReadAll(r.Body)
+		body = string(bts)
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{})
+	require.NoError(t, Send(ctx, http.MethodPut, srv.URL, "text/plain", []byte("hi"), nil))
+	require.Equal(t, http.MethodPut, method)
+	require.Equal(t, "text/plain", contentType)
+	require.Equal(t, "hi", body)
+}
@@ -48,6 +48,9 @@ const (
 	Signature
 	// UploadableSourceArchive is the archive with the current commit source code.
 	UploadableSourceArchive
+	// RepositoryFile is a file of a linux package repository, either a
+	// package or its metadata.
+	RepositoryFile
 )
 
 func (t Type) String() string {
@@ -72,6 +75,8 @@ func (t Type) String() string {
 		return "Signature"
 	case UploadableSourceArchive:
 		return "Source"
+	case RepositoryFile:
+		return "Repository File"
 	default:
 		return "unknown"
 	}
@@ -306,6 +306,7 @@ func TestTypeToString(t *testing.T) {
 		Checksum,
 		Signature,
 		UploadableSourceArchive,
+		RepositoryFile,
 	} {
 		t.Run(a.String(), func(t *testing.T) {
 			require.NotEqual(t, "unknown", a.String())
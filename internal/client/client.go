@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -31,9 +32,54 @@ func (r Repo) String() string {
 	return r.Owner + "/" + r.Name
 }
 
+// PullRequest is a merged pull request, or merge request on GitLab.
+type PullRequest struct {
+	Number int
+	Title  string
+	Author string
+	Labels []string
+	URL    string
+	SHA    string
+}
+
+// Release modes, telling what to do with the notes of an already existing
+// release.
+const (
+	ReleaseModeKeepExisting = "keep-existing"
+	ReleaseModeAppend       = "append"
+	ReleaseModePrepend      = "prepend"
+	ReleaseModeReplace      = "replace"
+)
+
+// releaseNotes returns the notes to set on a release which already has the
+// existing ones, according to the release mode. Notes are not appended nor
+// prepended again if they are already there, so rerunning a release doesn't
+// duplicate them.
+func releaseNotes(mode, existing, current string) string {
+	if existing == "" {
+		return current
+	}
+	if (mode == ReleaseModeAppend || mode == ReleaseModePrepend) && strings.Contains(existing, current) {
+		return existing
+	}
+	switch mode {
+	case ReleaseModeAppend:
+		return existing + "\n\n" + current
+	case ReleaseModePrepend:
+		return current + "\n\n" + existing
+	case ReleaseModeReplace:
+		return current
+	default:
+		return existing
+	}
+}
+
 // Client interface.
 type Client interface {
 	CloseMilestone(ctx *context.Context, repo Repo, title string) (err error)
+	CreateMilestone(ctx *context.Context, repo Repo, title string) (err error)
+	MoveOpenIssues(ctx *context.Context, repo Repo, from, to string) (err error)
+	PullRequests(ctx *context.Context, repo Repo, commits []string) (prs []PullRequest, err error)
 	CreateRelease(ctx *context.Context, body string) (releaseID string, err error)
 	ReleaseURLTemplate(ctx *context.Context) (string, error)
 	CreateFile(ctx *context.Context, commitAuthor config.CommitAuthor, repo Repo, content []byte, path, message string) (err error)
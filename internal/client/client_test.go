@@ -58,3 +58,31 @@ func TestClientNewGitLab(t *testing.T) {
 	_, ok := client.(*gitlabClient)
 	require.True(t, ok)
 }
+
+func TestReleaseNotes(t *testing.T) {
+	for mode, expected := range map[string]string{
+		"":                      "existing",
+		ReleaseModeKeepExisting: "existing",
+		ReleaseModeAppend:       "existing\n\ncurrent",
+		ReleaseModePrepend:      "current\n\nexisting",
+		ReleaseModeReplace:      "current",
+	} {
+		t.Run(mode, func(t *testing.T) {
+			require.Equal(t, expected, releaseNotes(mode, "existing", "current"))
+			require.Equal(t, "current", releaseNotes(mode, "", "current"))
+		})
+	}
+}
+
+func TestReleaseNotesRerun(t *testing.T) {
+	for mode, expected := range map[string]string{
+		ReleaseModeAppend:  "existing\n\ncurrent",
+		ReleaseModePrepend: "current\n\nexisting",
+	} {
+		t.Run(mode, func(t *testing.T) {
+			notes := releaseNotes(mode, "existing", "current")
+			notes = releaseNotes(mode, notes, "current")
+			require.Equal(t, expected, notes)
+		})
+	}
+}
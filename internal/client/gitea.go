@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"sort"
 	"strconv"
+	"time"
 
 	"code.gitea.io/sdk/gitea"
 	"github.com/apex/log"
@@ -76,6 +78,164 @@ func (c *giteaClient) CloseMilestone(ctx *context.Context, repo Repo, title stri
 	return err
 }
 
+// CreateMilestone creates a milestone, if it doesn't exist yet.
+func (c *giteaClient) CreateMilestone(ctx *context.Context, repo Repo, title string) error {
+	_, resp, err := c.client.GetMilestoneByName(repo.Owner, repo.Name, title)
+	if err == nil {
+		return nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return err
+	}
+
+	_, _, err = c.client.CreateMilestone(repo.Owner, repo.Name, gitea.CreateMilestoneOption{
+		Title: title,
+	})
+	return err
+}
+
+// MoveOpenIssues moves the open issues and pull requests of a milestone to
+// another one.
+func (c *giteaClient) MoveOpenIssues(ctx *context.Context, repo Repo, from, to string) error {
+	if _, resp, err := c.client.GetMilestoneByName(repo.Owner, repo.Name, from); err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return ErrNoMilestoneFound{Title: from}
+		}
+		return err
+	}
+
+	target, resp, err := c.client.GetMilestoneByName(repo.Owner, repo.Name, to)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return ErrNoMilestoneFound{Title: to}
+		}
+		return err
+	}
+
+	// pull requests are listed as issues as well.
+	opts := gitea.ListIssueOption{
+		ListOptions: gitea.ListOptions{Page: 1, PageSize: 50},
+		State:       gitea.StateOpen,
+		Type:        gitea.IssueTypeAll,
+		Milestones:  []string{from},
+	}
+
+	// the issues are only moved once all of them are listed, as moving them
+	// changes the pages.
+	var issues []*gitea.Issue
+	for {
+		page, _, err := c.client.ListRepoIssues(repo.Owner, repo.Name, opts)
+		if err != nil {
+			return err
+		}
+
+		issues = append(issues, page...)
+
+		if len(page) < opts.PageSize {
+			break
+		}
+
+		opts.Page++
+	}
+
+	for _, issue := range issues {
+		if _, _, err := c.client.EditIssue(repo.Owner, repo.Name, issue.Index, gitea.EditIssueOption{
+			Milestone: &target.ID,
+		}); err != nil {
+			return fmt.Errorf("failed to move issue #%d: %w", issue.Index, err)
+		}
+	}
+
+	return nil
+}
+
+// PullRequests returns the merged pull requests which introduced the given
+// commits.
+// Gitea can't list the pull requests of a commit, so the closed ones are
+// matched by their merge commit instead.
+func (c *giteaClient) PullRequests(ctx *context.Context, repo Repo, commits []string) ([]PullRequest, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	index := map[string]int{}
+	for i, sha := range commits {
+		index[sha] = i
+	}
+
+	// pull requests merged in the range were updated after its oldest commit
+	// was committed, so the recently updated ones are listed until then.
+	oldest, _, err := c.client.GetSingleCommit(repo.Owner, repo.Name, commits[len(commits)-1])
+	if err != nil {
+		return nil, err
+	}
+	var since time.Time
+	if oldest.RepoCommit != nil && oldest.RepoCommit.Committer != nil {
+		since, err = time.Parse(time.RFC3339, oldest.RepoCommit.Committer.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse commit date: %w", err)
+		}
+	}
+
+	opts := gitea.ListPullRequestsOptions{
+		ListOptions: gitea.ListOptions{Page: 1, PageSize: 50},
+		State:       gitea.StateClosed,
+		Sort:        "recentupdate",
+	}
+
+	var prs []*gitea.PullRequest
+	for {
+		page, _, err := c.client.ListRepoPullRequests(repo.Owner, repo.Name, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, pr := range page {
+			if !pr.HasMerged || pr.MergedCommitID == nil {
+				continue
+			}
+			if _, ok := index[*pr.MergedCommitID]; ok {
+				prs = append(prs, pr)
+			}
+		}
+
+		if len(page) < opts.PageSize {
+			break
+		}
+		if last := page[len(page)-1]; last.Updated != nil && last.Updated.Before(since) {
+			break
+		}
+
+		opts.Page++
+	}
+
+	// keep the order of the given commits, like the other clients.
+	sort.SliceStable(prs, func(i, j int) bool {
+		return index[*prs[i].MergedCommitID] < index[*prs[j].MergedCommitID]
+	})
+
+	result := make([]PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		var labels []string
+		for _, label := range pr.Labels {
+			labels = append(labels, label.Name)
+		}
+		var author string
+		if pr.Poster != nil {
+			author = pr.Poster.UserName
+		}
+		result = append(result, PullRequest{
+			Number: int(pr.Index),
+			Title:  pr.Title,
+			Author: author,
+			Labels: labels,
+			URL:    pr.HTMLURL,
+			SHA:    *pr.MergedCommitID,
+		})
+	}
+
+	return result, nil
+}
+
 // CreateFile creates a file in the repository at a given path
 // or updates the file if it exists.
 func (c *giteaClient) CreateFile(
@@ -190,8 +350,8 @@ func (c *giteaClient) updateRelease(ctx *context.Context, title, body string, id
 	return release, nil
 }
 
-// CreateRelease creates a new release or updates it by keeping
-// the release notes if it exists.
+// CreateRelease creates a new release or updates it, handling the existing
+// release notes according to the release mode.
 func (c *giteaClient) CreateRelease(ctx *context.Context, body string) (string, error) {
 	var release *gitea.Release
 	var err error
@@ -213,9 +373,7 @@ func (c *giteaClient) CreateRelease(ctx *context.Context, body string) (string,
 	}
 
 	if release != nil {
-		if release.Note != "" {
-			body = release.Note
-		}
+		body = releaseNotes(releaseConfig.Mode, release.Note, body)
 		release, err = c.updateRelease(ctx, title, body, release.ID)
 		if err != nil {
 			return "", err
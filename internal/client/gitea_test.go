@@ -1,11 +1,14 @@
 package client
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"code.gitea.io/sdk/gitea"
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -318,6 +321,33 @@ func (s *GiteaCreateReleaseSuite) TestSuccessUpdatingRelease() {
 	require.NoError(t, err)
 }
 
+func (s *GiteaCreateReleaseSuite) TestSuccessUpdatingReleaseAppending() {
+	t := s.T()
+	s.ctx.Config.Release.Mode = ReleaseModeAppend
+	existingRelease := gitea.Release{
+		ID:      666,
+		TagName: s.tag,
+		Note:    s.description,
+	}
+	resp, err := httpmock.NewJsonResponder(200, []gitea.Release{existingRelease})
+	require.NoError(t, err)
+	httpmock.RegisterResponder("GET", s.releasesURL, resp)
+	var note string
+	httpmock.RegisterResponder("PATCH", s.releaseURL, func(req *http.Request) (*http.Response, error) {
+		var opts gitea.EditReleaseOption
+		if err := json.NewDecoder(req.Body).Decode(&opts); err != nil {
+			return nil, err
+		}
+		note = opts.Note
+		return httpmock.NewJsonResponse(200, &existingRelease)
+	})
+
+	releaseID, err := s.client.CreateRelease(s.ctx, "NewDescription")
+	require.NoError(t, err)
+	require.Equal(t, "666", releaseID)
+	require.Equal(t, s.description+"\n\nNewDescription", note)
+}
+
 func (s *GiteaCreateReleaseSuite) TestErrorCreatingRelease() {
 	t := s.T()
 	httpmock.RegisterResponder("GET", s.releasesURL, httpmock.NewStringResponder(200, "[]"))
@@ -429,3 +459,57 @@ func TestGiteaReleaseURLTemplate(t *testing.T) {
 	expectedURL := "https://gitea.com/owner/name/releases/download/{{ .Tag }}/{{ .ArtifactName }}"
 	require.Equal(t, expectedURL, urlTpl)
 }
+
+type GiteaPullRequestsSuite struct {
+	GiteaReleasesTestSuite
+}
+
+func (s *GiteaPullRequestsSuite) TestPullRequests() {
+	t := s.T()
+	repoURL := fmt.Sprintf("%s/api/v1/repos/%s/%s", s.url, s.owner, s.repoName)
+	httpmock.RegisterResponder("GET", repoURL+"/git/commits/aaa", httpmock.NewStringResponder(200, `{"sha": "aaa", "commit": {"committer": {"date": "2021-06-01T00:00:00Z"}}}`))
+
+	var pages []string
+	httpmock.RegisterResponder("GET", repoURL+"/pulls", func(req *http.Request) (*http.Response, error) {
+		require.Equal(t, "closed", req.URL.Query().Get("state"))
+		require.Equal(t, "recentupdate", req.URL.Query().Get("sort"))
+		page := req.URL.Query().Get("page")
+		pages = append(pages, page)
+		var prs []gitea.PullRequest
+		for i := 0; i < 50; i++ {
+			updated := time.Date(2021, 6, 10, 0, 0, 0, 0, time.UTC)
+			if page != "1" || i == 49 {
+				updated = time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)
+			}
+			prs = append(prs, gitea.PullRequest{Index: int64(100 + i), Updated: &updated})
+		}
+		merged := "bbb"
+		prs[0] = gitea.PullRequest{
+			Index:          1,
+			Title:          "fix: foo",
+			HTMLURL:        "https://gitea.example.com/owner/repoName/pulls/1",
+			HasMerged:      true,
+			MergedCommitID: &merged,
+			Poster:         &gitea.User{UserName: "someone"},
+			Labels:         []*gitea.Label{{Name: "bug"}},
+			Updated:        prs[0].Updated,
+		}
+		return httpmock.NewJsonResponse(200, prs)
+	})
+
+	prs, err := s.client.PullRequests(s.ctx, Repo{Owner: s.owner, Name: s.repoName}, []string{"bbb", "aaa"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1"}, pages)
+	require.Equal(t, []PullRequest{{
+		Number: 1,
+		Title:  "fix: foo",
+		Author: "someone",
+		Labels: []string{"bug"},
+		URL:    "https://gitea.example.com/owner/repoName/pulls/1",
+		SHA:    "bbb",
+	}}, prs)
+}
+
+func TestGiteaPullRequestsSuite(t *testing.T) {
+	suite.Run(t, new(GiteaPullRequestsSuite))
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 
 	"github.com/apex/log"
@@ -82,6 +83,159 @@ func (c *githubClient) CloseMilestone(ctx *context.Context, repo Repo, title str
 	return err
 }
 
+// CreateMilestone creates a milestone, if it doesn't exist yet.
+func (c *githubClient) CreateMilestone(ctx *context.Context, repo Repo, title string) error {
+	milestone, err := c.getMilestoneByTitle(ctx, repo, title)
+	if err != nil {
+		return err
+	}
+
+	if milestone != nil {
+		return nil
+	}
+
+	_, _, err = c.client.Issues.CreateMilestone(
+		ctx,
+		repo.Owner,
+		repo.Name,
+		&github.Milestone{Title: &title},
+	)
+
+	return err
+}
+
+// MoveOpenIssues moves the open issues and pull requests of a milestone to
+// another one.
+func (c *githubClient) MoveOpenIssues(ctx *context.Context, repo Repo, from, to string) error {
+	source, err := c.getMilestoneByTitle(ctx, repo, from)
+	if err != nil {
+		return err
+	}
+	if source == nil {
+		return ErrNoMilestoneFound{Title: from}
+	}
+
+	target, err := c.getMilestoneByTitle(ctx, repo, to)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return ErrNoMilestoneFound{Title: to}
+	}
+
+	// pull requests are listed as issues as well.
+	opts := &github.IssueListByRepoOptions{
+		Milestone:   strconv.Itoa(source.GetNumber()),
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	// the issues are only moved once all of them are listed, as moving them
+	// changes the pages.
+	var issues []*github.Issue
+	for {
+		page, resp, err := c.client.Issues.ListByRepo(
+			ctx,
+			repo.Owner,
+			repo.Name,
+			opts,
+		)
+		if err != nil {
+			return err
+		}
+
+		issues = append(issues, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		opts.Page = resp.NextPage
+	}
+
+	for _, issue := range issues {
+		if _, _, err := c.client.Issues.Edit(
+			ctx,
+			repo.Owner,
+			repo.Name,
+			issue.GetNumber(),
+			&github.IssueRequest{Milestone: target.Number},
+		); err != nil {
+			return fmt.Errorf("failed to move issue #%d: %w", issue.GetNumber(), err)
+		}
+	}
+
+	return nil
+}
+
+// PullRequests returns the merged pull requests which introduced the given
+// commits.
+func (c *githubClient) PullRequests(ctx *context.Context, repo Repo, commits []string) ([]PullRequest, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	index := map[string]int{}
+	for i, sha := range commits {
+		index[sha] = i
+	}
+
+	// pull requests merged in the range were updated after its oldest commit
+	// was committed, so the recently updated ones are listed until then.
+	oldest, _, err := c.client.Git.GetCommit(ctx, repo.Owner, repo.Name, commits[len(commits)-1])
+	if err != nil {
+		return nil, err
+	}
+	since := oldest.GetCommitter().GetDate()
+
+	opts := &github.PullRequestListOptions{
+		State:       "closed",
+		Sort:        "updated",
+		Direction:   "desc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	var prs []*github.PullRequest
+	for {
+		page, resp, err := c.client.PullRequests.List(ctx, repo.Owner, repo.Name, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range page {
+			if pr.MergedAt == nil {
+				continue
+			}
+			if _, ok := index[pr.GetMergeCommitSHA()]; ok {
+				prs = append(prs, pr)
+			}
+		}
+		if resp.NextPage == 0 || len(page) == 0 || page[len(page)-1].GetUpdatedAt().Before(since) {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	// keep the order of the given commits, like the other clients.
+	sort.SliceStable(prs, func(i, j int) bool {
+		return index[prs[i].GetMergeCommitSHA()] < index[prs[j].GetMergeCommitSHA()]
+	})
+
+	result := make([]PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		var labels []string
+		for _, label := range pr.Labels {
+			labels = append(labels, label.GetName())
+		}
+		result = append(result, PullRequest{
+			Number: pr.GetNumber(),
+			Title:  pr.GetTitle(),
+			Author: pr.GetUser().GetLogin(),
+			Labels: labels,
+			URL:    pr.GetHTMLURL(),
+			SHA:    pr.GetMergeCommitSHA(),
+		})
+	}
+	return result, nil
+}
+
 func (c *githubClient) CreateFile(
 	ctx *context.Context,
 	commitAuthor config.CommitAuthor,
@@ -163,10 +317,7 @@ func (c *githubClient) CreateRelease(ctx *context.Context, body string) (string,
 			data,
 		)
 	} else {
-		// keep the pre-existing release notes
-		if release.GetBody() != "" {
-			data.Body = release.Body
-		}
+		data.Body = github.String(releaseNotes(ctx.Config.Release.Mode, release.GetBody(), body))
 		release, _, err = c.client.Repositories.EditRelease(
 			ctx,
 			ctx.Config.Release.GitHub.Owner,
@@ -1,6 +1,11 @@
 package client
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -95,3 +100,198 @@ func TestGitHubCreateReleaseWrongNameTemplate(t *testing.T) {
 	require.Empty(t, str)
 	require.EqualError(t, err, `template: tmpl:1: unclosed action`)
 }
+
+func githubMilestonesServer(t *testing.T, edits *[]string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/name/milestones":
+			_, _ = io.WriteString(w, `[{"number": 1, "title": "v1.4.0"}, {"number": 2, "title": "v1.5.0"}]`)
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/owner/name/milestones":
+			bts, _ := io.ReadAll(r.Body)
+			*edits = append(*edits, "create "+strings.TrimSpace(string(bts)))
+			_, _ = io.WriteString(w, `{"number": 3}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/name/issues":
+			require.Equal(t, "1", r.URL.Query().Get("milestone"))
+			require.Equal(t, "open", r.URL.Query().Get("state"))
+			_, _ = io.WriteString(w, `[{"number": 10}, {"number": 11}]`)
+		case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/repos/owner/name/issues/"):
+			bts, _ := io.ReadAll(r.Body)
+			*edits = append(*edits, "edit "+r.URL.Path+" "+strings.TrimSpace(string(bts)))
+			_, _ = io.WriteString(w, `{}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func githubMilestonesClient(t *testing.T, url string) (*context.Context, Client) {
+	t.Helper()
+	ctx := context.New(config.Project{
+		GitHubURLs: config.GitHubURLs{
+			API:    url + "/",
+			Upload: url + "/",
+		},
+	})
+	client, err := NewGitHub(ctx, "token")
+	require.NoError(t, err)
+	return ctx, client
+}
+
+func TestGitHubCreateMilestone(t *testing.T) {
+	var edits []string
+	srv := githubMilestonesServer(t, &edits)
+	ctx, client := githubMilestonesClient(t, srv.URL)
+	repo := Repo{Owner: "owner", Name: "name"}
+
+	require.NoError(t, client.CreateMilestone(ctx, repo, "v1.5.0"))
+	require.Empty(t, edits)
+
+	require.NoError(t, client.CreateMilestone(ctx, repo, "v1.6.0"))
+	require.Equal(t, []string{`create {"title":"v1.6.0"}`}, edits)
+}
+
+func TestGitHubMoveOpenIssues(t *testing.T) {
+	var edits []string
+	srv := githubMilestonesServer(t, &edits)
+	ctx, client := githubMilestonesClient(t, srv.URL)
+	repo := Repo{Owner: "owner", Name: "name"}
+
+	require.NoError(t, client.MoveOpenIssues(ctx, repo, "v1.4.0", "v1.5.0"))
+	require.Equal(t, []string{
+		`edit /repos/owner/name/issues/10 {"milestone":2}`,
+		`edit /repos/owner/name/issues/11 {"milestone":2}`,
+	}, edits)
+
+	require.EqualError(t, client.MoveOpenIssues(ctx, repo, "v1.3.0", "v1.5.0"), "no milestone found: v1.3.0")
+	require.EqualError(t, client.MoveOpenIssues(ctx, repo, "v1.4.0", "v1.6.0"), "no milestone found: v1.6.0")
+}
+
+func TestGitHubCreateReleaseExisting(t *testing.T) {
+	for mode, expected := range map[string]string{
+		ReleaseModeKeepExisting: "existing notes",
+		ReleaseModeAppend:       "existing notes\n\nnew notes",
+		ReleaseModePrepend:      "new notes\n\nexisting notes",
+		ReleaseModeReplace:      "new notes",
+	} {
+		t.Run(mode, func(t *testing.T) {
+			var body string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/name/releases/tags/v1.0.0":
+					_, _ = io.WriteString(w, `{"id": 1, "body": "existing notes"}`)
+				case r.Method == http.MethodPatch && r.URL.Path == "/repos/owner/name/releases/1":
+					var release struct {
+						Body string `json:"body"`
+					}
+					require.NoError(t, json.NewDecoder(r.Body).Decode(&release))
+					body = release.Body
+					_, _ = io.WriteString(w, `{"id": 1}`)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer srv.Close()
+
+			ctx, client := githubMilestonesClient(t, srv.URL)
+			ctx.Config.Release = config.Release{
+				GitHub:       config.Repo{Owner: "owner", Name: "name"},
+				NameTemplate: "{{ .Tag }}",
+				Mode:         mode,
+			}
+			ctx.Git.CurrentTag = "v1.0.0"
+
+			id, err := client.CreateRelease(ctx, "new notes")
+			require.NoError(t, err)
+			require.Equal(t, "1", id)
+			require.Equal(t, expected, body)
+		})
+	}
+}
+
+func TestGitHubCreateReleaseAppendTwice(t *testing.T) {
+	body := "existing notes"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/owner/name/releases/tags/v1.0.0":
+			require.NoError(t, json.NewEncoder(w).Encode(map[string]interface{}{"id": 1, "body": body}))
+		case r.Method == http.MethodPatch && r.URL.Path == "/repos/owner/name/releases/1":
+			var release struct {
+				Body string `json:"body"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&release))
+			body = release.Body
+			_, _ = io.WriteString(w, `{"id": 1}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx, client := githubMilestonesClient(t, srv.URL)
+	ctx.Config.Release = config.Release{
+		GitHub:       config.Repo{Owner: "owner", Name: "name"},
+		NameTemplate: "{{ .Tag }}",
+		Mode:         ReleaseModeAppend,
+	}
+	ctx.Git.CurrentTag = "v1.0.0"
+
+	for i := 0; i < 2; i++ {
+		_, err := client.CreateRelease(ctx, "new notes")
+		require.NoError(t, err)
+	}
+	require.Equal(t, "existing notes\n\nnew notes", body)
+}
+
+func TestGitHubPullRequests(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/name/git/commits/aaa":
+			_, _ = io.WriteString(w, `{"sha": "aaa", "committer": {"date": "2021-06-01T00:00:00Z"}}`)
+		case "/repos/owner/name/pulls":
+			require.Equal(t, "closed", r.URL.Query().Get("state"))
+			require.Equal(t, "updated", r.URL.Query().Get("sort"))
+			require.Equal(t, "desc", r.URL.Query().Get("direction"))
+			pages = append(pages, r.URL.Query().Get("page"))
+			w.Header().Set("Link", `<`+r.URL.Path+`?page=3>; rel="next"`)
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", `<`+r.URL.Path+`?page=2>; rel="next"`)
+				_, _ = io.WriteString(w, `[
+					{"number": 3, "title": "feat: bar", "merged_at": "2021-06-09T00:00:00Z", "merge_commit_sha": "ccc", "updated_at": "2021-06-10T00:00:00Z"},
+					{"number": 2, "title": "closed", "merge_commit_sha": "zzz", "updated_at": "2021-06-09T00:00:00Z"}
+				]`)
+				return
+			}
+			_, _ = io.WriteString(w, `[
+				{"number": 1, "title": "fix: foo", "html_url": "https://github.com/owner/name/pull/1", "user": {"login": "someone"}, "labels": [{"name": "bug"}], "merged_at": "2021-06-05T00:00:00Z", "merge_commit_sha": "bbb", "updated_at": "2021-06-05T00:00:00Z"},
+				{"number": 0, "title": "old", "merged_at": "2021-05-01T00:00:00Z", "merge_commit_sha": "yyy", "updated_at": "2021-05-01T00:00:00Z"}
+			]`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx, client := githubMilestonesClient(t, srv.URL)
+	prs, err := client.PullRequests(ctx, Repo{Owner: "owner", Name: "name"}, []string{"ccc", "bbb", "aaa"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "2"}, pages)
+	require.Equal(t, []PullRequest{
+		{Number: 3, Title: "feat: bar", SHA: "ccc"},
+		{
+			Number: 1,
+			Title:  "fix: foo",
+			Author: "someone",
+			Labels: []string{"bug"},
+			URL:    "https://github.com/owner/name/pull/1",
+			SHA:    "bbb",
+		},
+	}, prs)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"sort"
 
 	"github.com/apex/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -74,6 +75,187 @@ func (c *gitlabClient) CloseMilestone(ctx *context.Context, repo Repo, title str
 	return err
 }
 
+// CreateMilestone creates a milestone, if it doesn't exist yet.
+func (c *gitlabClient) CreateMilestone(ctx *context.Context, repo Repo, title string) error {
+	milestone, err := c.getMilestoneByTitle(repo, title)
+	if err != nil {
+		return err
+	}
+
+	if milestone != nil {
+		return nil
+	}
+
+	_, _, err = c.client.Milestones.CreateMilestone(
+		repo.String(),
+		&gitlab.CreateMilestoneOptions{Title: &title},
+	)
+
+	return err
+}
+
+// MoveOpenIssues moves the open issues and merge requests of a milestone to
+// another one.
+func (c *gitlabClient) MoveOpenIssues(ctx *context.Context, repo Repo, from, to string) error {
+	source, err := c.getMilestoneByTitle(repo, from)
+	if err != nil {
+		return err
+	}
+	if source == nil {
+		return ErrNoMilestoneFound{Title: from}
+	}
+
+	target, err := c.getMilestoneByTitle(repo, to)
+	if err != nil {
+		return err
+	}
+	if target == nil {
+		return ErrNoMilestoneFound{Title: to}
+	}
+
+	openedState := "opened"
+
+	// the issues are only moved once all of them are listed, as moving them
+	// changes the pages.
+	issuesOpts := &gitlab.ListProjectIssuesOptions{
+		Milestone:   &source.Title,
+		State:       &openedState,
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}
+	var issues []*gitlab.Issue
+	for {
+		page, resp, err := c.client.Issues.ListProjectIssues(repo.String(), issuesOpts)
+		if err != nil {
+			return err
+		}
+
+		issues = append(issues, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		issuesOpts.Page = resp.NextPage
+	}
+
+	mergeRequestsOpts := &gitlab.ListProjectMergeRequestsOptions{
+		Milestone:   &source.Title,
+		State:       &openedState,
+		ListOptions: gitlab.ListOptions{PerPage: 100},
+	}
+	var mergeRequests []*gitlab.MergeRequest
+	for {
+		page, resp, err := c.client.MergeRequests.ListProjectMergeRequests(repo.String(), mergeRequestsOpts)
+		if err != nil {
+			return err
+		}
+
+		mergeRequests = append(mergeRequests, page...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		mergeRequestsOpts.Page = resp.NextPage
+	}
+
+	for _, issue := range issues {
+		if _, _, err := c.client.Issues.UpdateIssue(
+			repo.String(),
+			issue.IID,
+			&gitlab.UpdateIssueOptions{MilestoneID: &target.ID},
+		); err != nil {
+			return fmt.Errorf("failed to move issue #%d: %w", issue.IID, err)
+		}
+	}
+
+	for _, mergeRequest := range mergeRequests {
+		if _, _, err := c.client.MergeRequests.UpdateMergeRequest(
+			repo.String(),
+			mergeRequest.IID,
+			&gitlab.UpdateMergeRequestOptions{MilestoneID: &target.ID},
+		); err != nil {
+			return fmt.Errorf("failed to move merge request !%d: %w", mergeRequest.IID, err)
+		}
+	}
+
+	return nil
+}
+
+// PullRequests returns the merged merge requests which introduced the given
+// commits.
+func (c *gitlabClient) PullRequests(ctx *context.Context, repo Repo, commits []string) ([]PullRequest, error) {
+	if len(commits) == 0 {
+		return nil, nil
+	}
+	index := map[string]int{}
+	for i, sha := range commits {
+		index[sha] = i
+	}
+
+	// merge requests merged in the range were updated after its oldest
+	// commit was committed, so only those are listed.
+	oldest, _, err := c.client.Commits.GetCommit(repo.String(), commits[len(commits)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &gitlab.ListProjectMergeRequestsOptions{
+		State:        gitlab.String("merged"),
+		OrderBy:      gitlab.String("updated_at"),
+		Sort:         gitlab.String("desc"),
+		UpdatedAfter: oldest.CommittedDate,
+		ListOptions:  gitlab.ListOptions{PerPage: 100},
+	}
+	var mergeRequests []*gitlab.MergeRequest
+	for {
+		page, resp, err := c.client.MergeRequests.ListProjectMergeRequests(repo.String(), opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, mergeRequest := range page {
+			if _, ok := index[mergeRequestSHA(mergeRequest)]; ok {
+				mergeRequests = append(mergeRequests, mergeRequest)
+			}
+		}
+		if resp.NextPage == 0 || len(page) == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	// keep the order of the given commits, like the other clients.
+	sort.SliceStable(mergeRequests, func(i, j int) bool {
+		return index[mergeRequestSHA(mergeRequests[i])] < index[mergeRequestSHA(mergeRequests[j])]
+	})
+
+	result := make([]PullRequest, 0, len(mergeRequests))
+	for _, mergeRequest := range mergeRequests {
+		var author string
+		if mergeRequest.Author != nil {
+			author = mergeRequest.Author.Username
+		}
+		result = append(result, PullRequest{
+			Number: mergeRequest.IID,
+			Title:  mergeRequest.Title,
+			Author: author,
+			Labels: mergeRequest.Labels,
+			URL:    mergeRequest.WebURL,
+			SHA:    mergeRequestSHA(mergeRequest),
+		})
+	}
+	return result, nil
+}
+
+// mergeRequestSHA returns the commit a merge request was merged with, which
+// is its squash commit if it was squashed without a merge commit.
+func mergeRequestSHA(mergeRequest *gitlab.MergeRequest) string {
+	if mergeRequest.MergeCommitSHA != "" {
+		return mergeRequest.MergeCommitSHA
+	}
+	return mergeRequest.SquashCommitSHA
+}
+
 // CreateFile gets a file in the repository at a given path
 // and updates if it exists or creates it for later pipes in the pipeline.
 func (c *gitlabClient) CreateFile(
@@ -242,10 +424,7 @@ func (c *gitlabClient) CreateRelease(ctx *context.Context, body string) (release
 		}
 		log.WithField("name", release.Name).Info("release created")
 	} else {
-		desc := body
-		if release != nil && release.DescriptionHTML != "" {
-			desc = release.DescriptionHTML
-		}
+		desc := releaseNotes(ctx.Config.Release.Mode, release.Description, body)
 
 		release, _, err = c.client.Releases.UpdateRelease(projectID, tagName, &gitlab.UpdateReleaseOptions{
 			Name:        &name,
@@ -1,6 +1,10 @@
 package client
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/goreleaser/goreleaser/pkg/config"
@@ -30,3 +34,112 @@ func TestGitLabReleaseURLTemplate(t *testing.T) {
 	expectedURL := "https://gitlab.com/owner/name/-/releases/{{ .Tag }}/downloads/{{ .ArtifactName }}"
 	require.Equal(t, expectedURL, urlTpl)
 }
+
+func TestGitLabCreateReleaseExisting(t *testing.T) {
+	var description string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v4/" {
+			// used by the client to configure its rate limiter
+			return
+		}
+		if r.URL.Path != "/api/v4/projects/owner/name/releases/v1.0.0" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			_, _ = io.WriteString(w, `{"tag_name": "v1.0.0", "description": "existing notes", "description_html": "<p>existing notes</p>"}`)
+		case http.MethodPut:
+			var release struct {
+				Description string `json:"description"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&release))
+			description = release.Description
+			_, _ = io.WriteString(w, `{"tag_name": "v1.0.0"}`)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		GitLabURLs: config.GitLabURLs{
+			API: srv.URL,
+		},
+		Release: config.Release{
+			GitLab: config.Repo{
+				Owner: "owner",
+				Name:  "name",
+			},
+			NameTemplate: "{{ .Tag }}",
+			Mode:         ReleaseModePrepend,
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	client, err := NewGitLab(ctx, "token")
+	require.NoError(t, err)
+
+	id, err := client.CreateRelease(ctx, "new notes")
+	require.NoError(t, err)
+	require.Equal(t, "v1.0.0", id)
+	require.Equal(t, "new notes\n\nexisting notes", description)
+}
+
+func TestGitLabPullRequests(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v4/":
+			// used by the client to configure its rate limiter
+		case "/api/v4/projects/owner/name/repository/commits/aaa":
+			_, _ = io.WriteString(w, `{"id": "aaa", "committed_date": "2021-06-01T00:00:00Z"}`)
+		case "/api/v4/projects/owner/name/merge_requests":
+			require.Equal(t, "merged", r.URL.Query().Get("state"))
+			require.Equal(t, "updated_at", r.URL.Query().Get("order_by"))
+			require.Equal(t, "desc", r.URL.Query().Get("sort"))
+			require.Equal(t, "2021-06-01T00:00:00Z", r.URL.Query().Get("updated_after"))
+			pages = append(pages, r.URL.Query().Get("page"))
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("X-Next-Page", "2")
+				_, _ = io.WriteString(w, `[
+					{"iid": 3, "title": "feat: bar", "merge_commit_sha": "ccc", "author": {"username": "bar"}, "labels": ["enhancement"], "web_url": "https://gitlab.com/owner/name/-/merge_requests/3"},
+					{"iid": 2, "title": "other branch", "merge_commit_sha": "zzz"}
+				]`)
+				return
+			}
+			_, _ = io.WriteString(w, `[
+				{"iid": 1, "title": "fix: foo", "squash_commit_sha": "bbb"}
+			]`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		GitLabURLs: config.GitLabURLs{
+			API: srv.URL,
+		},
+	})
+	client, err := NewGitLab(ctx, "token")
+	require.NoError(t, err)
+
+	prs, err := client.PullRequests(ctx, Repo{Owner: "owner", Name: "name"}, []string{"ccc", "bbb", "aaa"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "2"}, pages)
+	require.Equal(t, []PullRequest{
+		{
+			Number: 3,
+			Title:  "feat: bar",
+			Author: "bar",
+			Labels: []string{"enhancement"},
+			URL:    "https://gitlab.com/owner/name/-/merge_requests/3",
+			SHA:    "ccc",
+		},
+		{Number: 1, Title: "fix: foo", SHA: "bbb"},
+	}, prs)
+
+	prs, err = client.PullRequests(ctx, Repo{Owner: "owner", Name: "name"}, nil)
+	require.NoError(t, err)
+	require.Empty(t, prs)
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	stdctx "context"
+	"fmt"
+	h "net/http"
+	"strings"
+
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	// AuthBasic authenticates with a username and password.
+	AuthBasic = "basic"
+	// AuthBearer authenticates with a bearer token.
+	AuthBearer = "bearer"
+	// AuthOAuth2ClientCredentials authenticates with a bearer token obtained
+	// with the OAuth2 client credentials flow.
+	AuthOAuth2ClientCredentials = "oauth2_client_credentials"
+)
+
+// envKey returns the name of the environment variable with the given suffix
+// for the given upload, e.g. UPLOAD_PRODUCTION_SECRET.
+func envKey(upload *config.Upload, kind, suffix string) string {
+	return fmt.Sprintf("%s_%s_%s", strings.ToUpper(kind), strings.ToUpper(upload.Name), suffix)
+}
+
+// checkAuth validates the auth configuration of the given upload.
+func checkAuth(ctx *context.Context, upload *config.Upload, kind string) error {
+	switch upload.Auth.Type {
+	case "", AuthBasic:
+		username := getUsername(ctx, upload, kind)
+		password := getPassword(ctx, upload, kind)
+		passwordEnv := envKey(upload, kind, "SECRET")
+
+		if password != "" && username == "" {
+			return misconfigured(kind, upload, fmt.Sprintf("'username' is required when '%s' environment variable is set", passwordEnv))
+		}
+
+		if username != "" && password == "" {
+			return misconfigured(kind, upload, fmt.Sprintf("environment variable '%s' is required when 'username' is set", passwordEnv))
+		}
+	case AuthBearer:
+		if key := envKey(upload, kind, "TOKEN"); ctx.Env[key] == "" {
+			return misconfigured(kind, upload, fmt.Sprintf("environment variable '%s' is required for bearer auth", key))
+		}
+	case AuthOAuth2ClientCredentials:
+		if upload.Auth.TokenURL == "" {
+			return misconfigured(kind, upload, "'token_url' is required for oauth2_client_credentials auth")
+		}
+		for _, suffix := range []string{"CLIENT_ID", "CLIENT_SECRET"} {
+			if key := envKey(upload, kind, suffix); ctx.Env[key] == "" {
+				return misconfigured(kind, upload, fmt.Sprintf("environment variable '%s' is required for oauth2_client_credentials auth", key))
+			}
+		}
+	default:
+		return misconfigured(kind, upload, "auth type must be 'basic', 'bearer' or 'oauth2_client_credentials'")
+	}
+
+	if (upload.Auth.ClientCert == "") != (upload.Auth.ClientKey == "") {
+		return misconfigured(kind, upload, "'client_cert' and 'client_key' must be set together")
+	}
+	return nil
+}
+
+// authHeaders returns the headers needed to authenticate the requests of the
+// given upload, if any.
+// Basic auth is set on the request itself, and oauth2 tokens are set by the
+// client returned by getHTTPClient.
+func authHeaders(ctx *context.Context, upload *config.Upload, kind string) map[string]string {
+	if upload.Auth.Type != AuthBearer {
+		return nil
+	}
+	return map[string]string{
+		"Authorization": "Bearer " + ctx.Env[envKey(upload, kind, "TOKEN")],
+	}
+}
+
+// oauth2Client wraps the given client so its requests are authenticated with
+// a token obtained with the OAuth2 client credentials flow.
+// The token is requested with the given client too, and is reused until it
+// expires.
+func oauth2Client(ctx *context.Context, upload *config.Upload, kind string, client *h.Client) (*h.Client, error) {
+	tokenURL, err := tmpl.New(ctx).Apply(upload.Auth.TokenURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve token_url: %w", err)
+	}
+	conf := clientcredentials.Config{
+		ClientID:     ctx.Env[envKey(upload, kind, "CLIENT_ID")],
+		ClientSecret: ctx.Env[envKey(upload, kind, "CLIENT_SECRET")],
+		TokenURL:     tokenURL,
+		Scopes:       upload.Auth.Scopes,
+	}
+	return conf.Client(stdctx.WithValue(ctx, oauth2.HTTPClient, client)), nil
+}
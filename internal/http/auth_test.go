@@ -0,0 +1,236 @@
+package http
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	h "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAuth(t *testing.T) {
+	ctx := context.New(config.Project{})
+	ctx.Env["TEST_A_TOKEN"] = "token"
+	ctx.Env["TEST_A_CLIENT_ID"] = "id"
+	ctx.Env["TEST_A_CLIENT_SECRET"] = "secret"
+	for name, tt := range map[string]struct {
+		upload config.Upload
+		err    string
+	}{
+		"bearer": {
+			upload: config.Upload{Name: "a", Auth: config.UploadAuth{Type: AuthBearer}},
+		},
+		"bearer without token": {
+			upload: config.Upload{Name: "b", Auth: config.UploadAuth{Type: AuthBearer}},
+			err:    "environment variable 'TEST_B_TOKEN' is required for bearer auth",
+		},
+		"oauth2": {
+			upload: config.Upload{Name: "a", Auth: config.UploadAuth{Type: AuthOAuth2ClientCredentials, TokenURL: "https://auth"}},
+		},
+		"oauth2 without token url": {
+			upload: config.Upload{Name: "a", Auth: config.UploadAuth{Type: AuthOAuth2ClientCredentials}},
+			err:    "'token_url' is required for oauth2_client_credentials auth",
+		},
+		"oauth2 without client id": {
+			upload: config.Upload{Name: "b", Auth: config.UploadAuth{Type: AuthOAuth2ClientCredentials, TokenURL: "https://auth"}},
+			err:    "environment variable 'TEST_B_CLIENT_ID' is required for oauth2_client_credentials auth",
+		},
+		"invalid type": {
+			upload: config.Upload{Name: "a", Auth: config.UploadAuth{Type: "digest"}},
+			err:    "auth type must be 'basic', 'bearer' or 'oauth2_client_credentials'",
+		},
+		"client cert": {
+			upload: config.Upload{Name: "a", Auth: config.UploadAuth{ClientCert: "cert.pem", ClientKey: "key.pem"}},
+		},
+		"client cert without key": {
+			upload: config.Upload{Name: "a", Auth: config.UploadAuth{ClientCert: "cert.pem"}},
+			err:    "'client_cert' and 'client_key' must be set together",
+		},
+	} {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			err := checkAuth(ctx, &tt.upload, "test")
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, fmt.Sprintf("test section '%s' is not configured properly (%s)", tt.upload.Name, tt.err))
+		})
+	}
+}
+
+func TestUploadBearer(t *testing.T) {
+	var auths []string
+	var m sync.Mutex
+	srv := httptest.NewServer(h.HandlerFunc(func(w h.ResponseWriter, r *h.Request) {
+		m.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		m.Unlock()
+		w.WriteHeader(h.StatusCreated)
+	}))
+	defer srv.Close()
+
+	ctx := authContext(t)
+	ctx.Env["TEST_A_TOKEN"] = "secret-token"
+	ctx.Env["TEST_A_USERNAME"] = "u"
+	ctx.Env["TEST_A_SECRET"] = "x"
+	require.NoError(t, Upload(ctx, []config.Upload{
+		{
+			Name:   "a",
+			Mode:   ModeArchive,
+			Method: h.MethodPut,
+			Target: srv.URL,
+			Auth:   config.UploadAuth{Type: AuthBearer},
+		},
+	}, "test", is2xx))
+	require.Equal(t, []string{"Bearer secret-token", "Bearer secret-token"}, auths)
+}
+
+func TestUploadOAuth2ClientCredentials(t *testing.T) {
+	var tokens int
+	var auths []string
+	var m sync.Mutex
+	mux := h.NewServeMux()
+	mux.HandleFunc("/token", func(w h.ResponseWriter, r *h.Request) {
+		id, secret, _ := r.BasicAuth()
+		if id != "id" || secret != "secret" || r.FormValue("scope") != "upload write" {
+			w.WriteHeader(h.StatusUnauthorized)
+			return
+		}
+		m.Lock()
+		tokens++
+		m.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"oauth-token","token_type":"bearer","expires_in":3600}`)
+	})
+	mux.HandleFunc("/upload/", func(w h.ResponseWriter, r *h.Request) {
+		m.Lock()
+		auths = append(auths, r.Header.Get("Authorization"))
+		m.Unlock()
+		w.WriteHeader(h.StatusCreated)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := authContext(t)
+	ctx.Env["TEST_A_CLIENT_ID"] = "id"
+	ctx.Env["TEST_A_CLIENT_SECRET"] = "secret"
+	require.NoError(t, Upload(ctx, []config.Upload{
+		{
+			Name:   "a",
+			Mode:   ModeArchive,
+			Method: h.MethodPut,
+			Target: srv.URL + "/upload/",
+			Auth: config.UploadAuth{
+				Type:     AuthOAuth2ClientCredentials,
+				TokenURL: srv.URL + "/token",
+				Scopes:   []string{"upload", "write"},
+			},
+		},
+	}, "test", is2xx))
+	require.Equal(t, 1, tokens)
+	require.Equal(t, []string{"Bearer oauth-token", "Bearer oauth-token"}, auths)
+}
+
+func TestUploadClientCert(t *testing.T) {
+	var subjects []string
+	var m sync.Mutex
+	srv := httptest.NewUnstartedServer(h.HandlerFunc(func(w h.ResponseWriter, r *h.Request) {
+		m.Lock()
+		for _, cert := range r.TLS.PeerCertificates {
+			subjects = append(subjects, cert.Subject.CommonName)
+		}
+		m.Unlock()
+		w.WriteHeader(h.StatusCreated)
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert} // nolint: gosec
+	srv.StartTLS()
+	defer srv.Close()
+
+	certFile, keyFile := clientCert(t)
+	ctx := authContext(t)
+	upload := config.Upload{
+		Name:         "a",
+		Mode:         ModeArchive,
+		Method:       h.MethodPut,
+		Target:       srv.URL,
+		TrustedCerts: cert(srv),
+		Auth: config.UploadAuth{
+			ClientCert: certFile,
+			ClientKey:  keyFile,
+		},
+	}
+	require.NoError(t, Upload(ctx, []config.Upload{upload}, "test", is2xx))
+	require.Equal(t, []string{"goreleaser", "goreleaser"}, subjects)
+
+	upload.Auth.ClientKey = certFile
+	err := Upload(ctx, []config.Upload{upload}, "test", is2xx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "test: failed to create http client: failed to load client certificate")
+}
+
+func is2xx(r *h.Response) error {
+	if r.StatusCode/100 == 2 {
+		return nil
+	}
+	return fmt.Errorf("unexpected http status code: %v", r.StatusCode)
+}
+
+func authContext(t *testing.T) *context.Context {
+	t.Helper()
+	folder := t.TempDir()
+	ctx := context.New(config.Project{ProjectName: "blah"})
+	for _, name := range []string{"a.tar", "a.deb"} {
+		file := filepath.Join(folder, name)
+		require.NoError(t, os.WriteFile(file, []byte("lorem ipsum"), 0o644))
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Name: name,
+			Path: file,
+			Type: artifact.UploadableArchive,
+		})
+	}
+	return ctx
+}
+
+// clientCert writes a self signed client certificate and its key, returning
+// their paths.
+func clientCert(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "goreleaser"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	folder := t.TempDir()
+	certFile := filepath.Join(folder, "cert.pem")
+	keyFile := filepath.Join(folder, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certFile, keyFile
+}
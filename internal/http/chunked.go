@@ -0,0 +1,164 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	h "net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const (
+	defaultChunkSize    = 8 * 1024 * 1024
+	defaultChunkRetries = 5
+	defaultChunkBackoff = time.Second
+)
+
+// uploadAssetInChunks uploads the asset in chunks of the configured size, each
+// one sent in its own request with a Content-Range header.
+// Failed chunks are retried with an exponential backoff, so a failure does not
+// restart the whole upload. Servers can also answer with a Range header with
+// the bytes they already have, in which case the upload resumes right after
+// them.
+// It returns the response of the last chunk.
+func uploadAssetInChunks(ctx *context.Context, client *h.Client, upload *config.Upload, target, username, secret string, headers map[string]string, a *asset, check ResponseChecker) (*h.Response, error) {
+	reader, ok := a.ReadCloser.(io.ReaderAt)
+	if !ok {
+		return nil, fmt.Errorf("chunked uploads require a file")
+	}
+
+	size := upload.Chunked.ChunkSize
+	if size <= 0 {
+		size = defaultChunkSize
+	}
+	if size > a.Size {
+		size = a.Size
+	}
+	buf := make([]byte, size)
+	var offset int64
+	var res *h.Response
+	for offset < a.Size {
+		end := offset + size
+		if end > a.Size {
+			end = a.Size
+		}
+		chunk := buf[:end-offset]
+		if _, err := reader.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read chunk: %w", err)
+		}
+
+		var err error
+		res, offset, err = uploadChunk(ctx, client, upload, target, username, secret, headers, chunk, offset, a.Size, check)
+		if err != nil {
+			return res, err
+		}
+		log.WithFields(log.Fields{
+			"instance": upload.Name,
+			"file":     path.Base(target),
+			"progress": fmt.Sprintf("%d%%", offset*100/a.Size),
+		}).Info("uploading")
+	}
+	return res, nil
+}
+
+// uploadChunk uploads the given chunk, retrying on network and server errors,
+// and returns the offset of the next chunk.
+func uploadChunk(ctx *context.Context, client *h.Client, upload *config.Upload, target, username, secret string, headers map[string]string, chunk []byte, offset, total int64, check ResponseChecker) (*h.Response, int64, error) {
+	chunkHeaders := map[string]string{
+		"Content-Range": fmt.Sprintf("bytes %d-%d/%d", offset, offset+int64(len(chunk))-1, total),
+	}
+	for k, v := range headers {
+		chunkHeaders[k] = v
+	}
+
+	backoff := upload.Chunked.Backoff
+	for attempt := 1; ; attempt++ {
+		res, next, retry, err := sendChunk(ctx, client, upload, target, username, secret, chunkHeaders, chunk, offset, check)
+		if err == nil {
+			return res, next, nil
+		}
+		if !retry || attempt > upload.Chunked.Retries {
+			return res, 0, err
+		}
+		log.WithError(err).WithFields(log.Fields{
+			"instance": upload.Name,
+			"range":    chunkHeaders["Content-Range"],
+			"attempt":  attempt,
+		}).Warnf("chunk upload failed, retrying in %s", backoff)
+		select {
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// sendChunk sends a single chunk, returning the offset of the next chunk and
+// whether the request should be retried in case of errors.
+func sendChunk(ctx *context.Context, client *h.Client, upload *config.Upload, target, username, secret string, headers map[string]string, chunk []byte, offset int64, check ResponseChecker) (*h.Response, int64, bool, error) {
+	req, err := newUploadRequest(ctx, upload.Method, target, username, secret, headers, &asset{
+		ReadCloser: io.NopCloser(bytes.NewReader(chunk)),
+		Size:       int64(len(chunk)),
+	})
+	if err != nil {
+		return nil, 0, false, err
+	}
+	log.Debugf("executing request: %s %s (headers: %v)", req.Method, req.URL, redactHeaders(req.Header))
+	res, err := client.Do(req)
+	if err != nil {
+		// If we got an error, and the context has been canceled,
+		// the context's error is probably more useful.
+		select {
+		case <-ctx.Done():
+			return nil, 0, false, ctx.Err()
+		default:
+		}
+		return nil, 0, true, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 500 || res.StatusCode == h.StatusTooManyRequests {
+		return res, 0, true, fmt.Errorf("server responded with %s", res.Status)
+	}
+
+	next := offset + int64(len(chunk))
+	if rng := res.Header.Get("Range"); rng != "" {
+		last, err := parseRange(rng)
+		if err != nil {
+			return res, 0, false, err
+		}
+		if last+1 <= offset {
+			return res, 0, true, fmt.Errorf("server did not receive the chunk, has %s", rng)
+		}
+		next = last + 1
+	}
+	// 308 Resume Incomplete answers intermediate chunks of resumable uploads
+	if res.StatusCode == h.StatusPermanentRedirect {
+		return res, next, false, nil
+	}
+	if err := check(res); err != nil {
+		return res, 0, false, err
+	}
+	return res, next, false, nil
+}
+
+// parseRange returns the last byte of a "bytes=0-1023" range header.
+func parseRange(rng string) (int64, error) {
+	parts := strings.SplitN(strings.TrimPrefix(rng, "bytes="), "-", 2)
+	if len(parts) != 2 || parts[0] != "0" {
+		return 0, fmt.Errorf("invalid range header: %s", rng)
+	}
+	last, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid range header: %s", rng)
+	}
+	return last, nil
+}
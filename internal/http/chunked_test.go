@@ -0,0 +1,248 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	h "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkServer stores the received chunks, calling the given function before
+// each one to decide how to answer it.
+type chunkServer struct {
+	lock    sync.Mutex
+	content []byte
+	ranges  []string
+	answer  func(n int, rng string, w h.ResponseWriter) bool
+}
+
+func (s *chunkServer) ServeHTTP(w h.ResponseWriter, r *h.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	rng := r.Header.Get("Content-Range")
+	s.ranges = append(s.ranges, rng)
+	if s.answer != nil && s.answer(len(s.ranges), rng, w) {
+		return
+	}
+	var start, end, total int
+	if _, err := fmt.Sscanf(rng, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+		w.WriteHeader(h.StatusBadRequest)
+		return
+	}
+	bts, err := io.ReadAll(r.Body)
+	if err != nil || len(bts) != end-start+1 || start != len(s.content) {
+		w.WriteHeader(h.StatusBadRequest)
+		return
+	}
+	s.content = append(s.content, bts...)
+	if len(s.content) < total {
+		w.Header().Set("Range", fmt.Sprintf("bytes=0-%d", len(s.content)-1))
+		w.WriteHeader(h.StatusPermanentRedirect)
+		return
+	}
+	w.WriteHeader(h.StatusCreated)
+}
+
+func chunkedContext(t *testing.T, content string) *context.Context {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "a.tar")
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	ctx := context.New(config.Project{ProjectName: "blah"})
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "a.tar",
+		Path: file,
+		Type: artifact.UploadableArchive,
+	})
+	return ctx
+}
+
+func chunkedUploads(url string) []config.Upload {
+	return []config.Upload{
+		{
+			Name:   "a",
+			Mode:   ModeArchive,
+			Method: h.MethodPut,
+			Target: url,
+			Chunked: config.UploadChunked{
+				Enabled:   true,
+				ChunkSize: 4,
+				Retries:   2,
+				Backoff:   time.Millisecond,
+			},
+		},
+	}
+}
+
+func TestUploadChunked(t *testing.T) {
+	server := &chunkServer{}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	ctx := chunkedContext(t, "lorem ipsum")
+	require.NoError(t, Upload(ctx, chunkedUploads(srv.URL), "test", is2xx))
+	require.Equal(t, "lorem ipsum", string(server.content))
+	require.Equal(t, []string{
+		"bytes 0-3/11",
+		"bytes 4-7/11",
+		"bytes 8-10/11",
+	}, server.ranges)
+}
+
+func TestUploadChunkedRetry(t *testing.T) {
+	server := &chunkServer{
+		answer: func(n int, _ string, w h.ResponseWriter) bool {
+			if n == 2 || n == 3 {
+				w.WriteHeader(h.StatusServiceUnavailable)
+				return true
+			}
+			return false
+		},
+	}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	ctx := chunkedContext(t, "lorem ipsum")
+	require.NoError(t, Upload(ctx, chunkedUploads(srv.URL), "test", is2xx))
+	require.Equal(t, "lorem ipsum", string(server.content))
+	require.Equal(t, []string{
+		"bytes 0-3/11",
+		"bytes 4-7/11",
+		"bytes 4-7/11",
+		"bytes 4-7/11",
+		"bytes 8-10/11",
+	}, server.ranges)
+}
+
+func TestUploadChunkedResume(t *testing.T) {
+	server := &chunkServer{}
+	server.answer = func(n int, _ string, w h.ResponseWriter) bool {
+		if n != 2 {
+			return false
+		}
+		// pretend only half of the chunk was received
+		server.content = append(server.content, []byte("m ")...)
+		w.Header().Set("Range", "bytes=0-5")
+		w.WriteHeader(h.StatusPermanentRedirect)
+		return true
+	}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	ctx := chunkedContext(t, "lorem ipsum")
+	require.NoError(t, Upload(ctx, chunkedUploads(srv.URL), "test", is2xx))
+	require.Equal(t, "lorem ipsum", string(server.content))
+	require.Equal(t, []string{
+		"bytes 0-3/11",
+		"bytes 4-7/11",
+		"bytes 6-9/11",
+		"bytes 10-10/11",
+	}, server.ranges)
+}
+
+func TestUploadChunkedGiveUp(t *testing.T) {
+	server := &chunkServer{
+		answer: func(n int, _ string, w h.ResponseWriter) bool {
+			w.WriteHeader(h.StatusBadGateway)
+			return true
+		},
+	}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	ctx := chunkedContext(t, "lorem ipsum")
+	err := Upload(ctx, chunkedUploads(srv.URL), "test", is2xx)
+	require.EqualError(t, err, "test: upload failed: server responded with 502 Bad Gateway")
+	require.Len(t, server.ranges, 3)
+}
+
+func TestUploadChunkedNoRetryOnClientErrors(t *testing.T) {
+	server := &chunkServer{
+		answer: func(n int, _ string, w h.ResponseWriter) bool {
+			w.WriteHeader(h.StatusForbidden)
+			return true
+		},
+	}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	ctx := chunkedContext(t, "lorem ipsum")
+	err := Upload(ctx, chunkedUploads(srv.URL), "test", is2xx)
+	require.EqualError(t, err, "test: upload failed: unexpected http status code: 403")
+	require.Len(t, server.ranges, 1)
+}
+
+func TestUploadInChunksRequiresFile(t *testing.T) {
+	ctx := context.New(config.Project{})
+	_, err := uploadAssetInChunks(ctx, h.DefaultClient, &config.Upload{}, "http://localhost", "", "", nil, &asset{
+		ReadCloser: io.NopCloser(strings.NewReader("a")),
+		Size:       1,
+	}, is2xx)
+	require.EqualError(t, err, "chunked uploads require a file")
+}
+
+func TestParseRange(t *testing.T) {
+	last, err := parseRange("bytes=0-1023")
+	require.NoError(t, err)
+	require.Equal(t, int64(1023), last)
+
+	for _, rng := range []string{"bytes=10-20", "bytes=0-", "nope"} {
+		_, err := parseRange(rng)
+		require.EqualError(t, err, "invalid range header: "+rng)
+	}
+}
+
+func TestDefaultsChunked(t *testing.T) {
+	uploads := []config.Upload{
+		{Name: "a"},
+		{Name: "b", Chunked: config.UploadChunked{Enabled: true}},
+		{Name: "c", Chunked: config.UploadChunked{Enabled: true, ChunkSize: 1024, Retries: 1, Backoff: time.Minute}},
+	}
+	require.NoError(t, Defaults(uploads))
+	require.Equal(t, config.UploadChunked{}, uploads[0].Chunked)
+	require.Equal(t, config.UploadChunked{
+		Enabled:   true,
+		ChunkSize: defaultChunkSize,
+		Retries:   defaultChunkRetries,
+		Backoff:   defaultChunkBackoff,
+	}, uploads[1].Chunked)
+	require.Equal(t, config.UploadChunked{
+		Enabled:   true,
+		ChunkSize: 1024,
+		Retries:   1,
+		Backoff:   time.Minute,
+	}, uploads[2].Chunked)
+}
+
+func TestCheckConfigChunked(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, CheckConfig(ctx, &config.Upload{
+		Name:    "a",
+		Target:  "http://blabla",
+		Mode:    ModeArchive,
+		Chunked: config.UploadChunked{Enabled: true},
+	}, "test"))
+	require.EqualError(t, CheckConfig(ctx, &config.Upload{
+		Name:       "a",
+		Target:     "http://blabla",
+		Mode:       ModeArchive,
+		BodyFormat: BodyFormatMultipart,
+		Chunked:    config.UploadChunked{Enabled: true},
+	}, "test"), "test section 'a' is not configured properly (chunked uploads can't be used with the multipart body_format)")
+	require.EqualError(t, CheckConfig(ctx, &config.Upload{
+		Name:    "a",
+		Target:  "http://blabla",
+		Mode:    ModeArchive,
+		Chunked: config.UploadChunked{Enabled: true, ChunkSize: -1},
+	}, "test"), "test section 'a' is not configured properly (chunk_size can't be negative)")
+}
@@ -25,6 +25,11 @@ const (
 	ModeBinary = "binary"
 	// ModeArchive uploads release archives.
 	ModeArchive = "archive"
+
+	// BodyFormatRaw sends the file as the request body.
+	BodyFormatRaw = "raw"
+	// BodyFormatMultipart sends the file in a multipart/form-data body.
+	BodyFormatMultipart = "multipart"
 )
 
 type asset struct {
@@ -80,6 +85,23 @@ func defaults(upload *config.Upload) {
 	if upload.Method == "" {
 		upload.Method = h.MethodPut
 	}
+	if upload.BodyFormat == "" {
+		upload.BodyFormat = BodyFormatRaw
+	}
+	if upload.BodyFormat == BodyFormatMultipart && upload.FileField == "" {
+		upload.FileField = "file"
+	}
+	if upload.Chunked.Enabled {
+		if upload.Chunked.ChunkSize == 0 {
+			upload.Chunked.ChunkSize = defaultChunkSize
+		}
+		if upload.Chunked.Retries == 0 {
+			upload.Chunked.Retries = defaultChunkRetries
+		}
+		if upload.Chunked.Backoff == 0 {
+			upload.Chunked.Backoff = defaultChunkBackoff
+		}
+	}
 }
 
 // CheckConfig validates an upload configuration returning a descriptive error when appropriate.
@@ -96,16 +118,20 @@ func CheckConfig(ctx *context.Context, upload *config.Upload, kind string) error
 		return misconfigured(kind, upload, "mode must be 'binary' or 'archive'")
 	}
 
-	username := getUsername(ctx, upload, kind)
-	password := getPassword(ctx, upload, kind)
-	passwordEnv := fmt.Sprintf("%s_%s_SECRET", strings.ToUpper(kind), strings.ToUpper(upload.Name))
+	if upload.BodyFormat != "" && upload.BodyFormat != BodyFormatRaw && upload.BodyFormat != BodyFormatMultipart {
+		return misconfigured(kind, upload, "body_format must be 'raw' or 'multipart'")
+	}
+
+	if upload.Chunked.Enabled && upload.BodyFormat == BodyFormatMultipart {
+		return misconfigured(kind, upload, "chunked uploads can't be used with the multipart body_format")
+	}
 
-	if password != "" && username == "" {
-		return misconfigured(kind, upload, fmt.Sprintf("'username' is required when '%s' environment variable is set", passwordEnv))
+	if upload.Chunked.Enabled && upload.Chunked.ChunkSize < 0 {
+		return misconfigured(kind, upload, "chunk_size can't be negative")
 	}
 
-	if username != "" && password == "" {
-		return misconfigured(kind, upload, fmt.Sprintf("environment variable '%s' is required when 'username' is set", passwordEnv))
+	if err := checkAuth(ctx, upload, kind); err != nil {
+		return err
 	}
 
 	if upload.TrustedCerts != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(upload.TrustedCerts)) {
@@ -121,14 +147,12 @@ func getUsername(ctx *context.Context, upload *config.Upload, kind string) strin
 		return upload.Username
 	}
 
-	key := fmt.Sprintf("%s_%s_USERNAME", strings.ToUpper(kind), strings.ToUpper(upload.Name))
-	return ctx.Env[key]
+	return ctx.Env[envKey(upload, kind, "USERNAME")]
 }
 
 // password is optional
 func getPassword(ctx *context.Context, upload *config.Upload, kind string) string {
-	key := fmt.Sprintf("%s_%s_SECRET", strings.ToUpper(kind), strings.ToUpper(upload.Name))
-	return ctx.Env[key]
+	return ctx.Env[envKey(upload, kind, "SECRET")]
 }
 
 func misconfigured(kind string, upload *config.Upload, reason string) error {
@@ -180,6 +204,13 @@ func Upload(ctx *context.Context, uploads []config.Upload, kind string, check Re
 		if len(upload.IDs) > 0 {
 			filter = artifact.And(filter, artifact.ByIDs(upload.IDs...))
 		}
+		if len(upload.Repositories) > 0 {
+			// repository files are only uploaded when explicitly asked for
+			filter = artifact.Or(
+				filter,
+				artifact.And(artifact.ByType(artifact.RepositoryFile), artifact.ByIDs(upload.Repositories...)),
+			)
+		}
 		if err := uploadWithFilter(ctx, &upload, filter, kind, check); err != nil {
 			return err
 		}
@@ -189,24 +220,31 @@ func Upload(ctx *context.Context, uploads []config.Upload, kind string, check Re
 }
 
 func uploadWithFilter(ctx *context.Context, upload *config.Upload, filter artifact.Filter, kind string, check ResponseChecker) error {
+	client, err := getHTTPClient(ctx, upload, kind)
+	if err != nil {
+		return fmt.Errorf("%s: failed to create http client: %w", kind, err)
+	}
 	artifacts := ctx.Artifacts.Filter(filter).List()
 	log.Debugf("will upload %d artifacts", len(artifacts))
 	g := semerrgroup.New(ctx.Parallelism)
 	for _, artifact := range artifacts {
 		artifact := artifact
 		g.Go(func() error {
-			return uploadAsset(ctx, upload, artifact, kind, check)
+			return uploadAsset(ctx, client, upload, artifact, kind, check)
 		})
 	}
 	return g.Wait()
 }
 
 // uploadAsset uploads file to target and logs all actions.
-func uploadAsset(ctx *context.Context, upload *config.Upload, artifact *artifact.Artifact, kind string, check ResponseChecker) error {
+func uploadAsset(ctx *context.Context, client *h.Client, upload *config.Upload, artifact *artifact.Artifact, kind string, check ResponseChecker) error {
 	// username and secret are optional since the server may not support/need
 	// basic authentication always
-	username := getUsername(ctx, upload, kind)
-	secret := getPassword(ctx, upload, kind)
+	var username, secret string
+	if upload.Auth.Type == "" || upload.Auth.Type == AuthBasic {
+		username = getUsername(ctx, upload, kind)
+		secret = getPassword(ctx, upload, kind)
+	}
 
 	// Generate the target url
 	targetURL, err := resolveTargetTemplate(ctx, upload, artifact)
@@ -234,6 +272,9 @@ func uploadAsset(ctx *context.Context, upload *config.Upload, artifact *artifact
 	log.Debugf("generated target url: %s", targetURL)
 
 	headers := map[string]string{}
+	for name, value := range authHeaders(ctx, upload, kind) {
+		headers[name] = value
+	}
 	if upload.CustomHeaders != nil {
 		for name, value := range upload.CustomHeaders {
 			resolvedValue, err := resolveHeaderTemplate(ctx, upload, artifact, value)
@@ -257,7 +298,18 @@ func uploadAsset(ctx *context.Context, upload *config.Upload, artifact *artifact
 		headers[upload.ChecksumHeader] = sum
 	}
 
-	res, err := uploadAssetToServer(ctx, upload, targetURL, username, secret, headers, asset, check)
+	if upload.BodyFormat == BodyFormatMultipart {
+		var contentType string
+		asset, contentType, err = newMultipartAsset(ctx, upload, artifact, asset)
+		if err != nil {
+			msg := fmt.Sprintf("%s: failed to create multipart body", kind)
+			log.WithError(err).WithField("instance", upload.Name).Error(msg)
+			return fmt.Errorf("%s: %w", msg, err)
+		}
+		headers["Content-Type"] = contentType
+	}
+
+	res, err := uploadAssetToServer(ctx, client, upload, targetURL, username, secret, headers, asset, check)
 	if err != nil {
 		msg := fmt.Sprintf("%s: upload failed", kind)
 		log.WithError(err).WithFields(log.Fields{
@@ -278,13 +330,17 @@ func uploadAsset(ctx *context.Context, upload *config.Upload, artifact *artifact
 }
 
 // uploadAssetToServer uploads the asset file to target.
-func uploadAssetToServer(ctx *context.Context, upload *config.Upload, target, username, secret string, headers map[string]string, a *asset, check ResponseChecker) (*h.Response, error) {
+func uploadAssetToServer(ctx *context.Context, client *h.Client, upload *config.Upload, target, username, secret string, headers map[string]string, a *asset, check ResponseChecker) (*h.Response, error) {
+	if upload.Chunked.Enabled && a.Size > 0 {
+		return uploadAssetInChunks(ctx, client, upload, target, username, secret, headers, a, check)
+	}
+
 	req, err := newUploadRequest(ctx, upload.Method, target, username, secret, headers, a)
 	if err != nil {
 		return nil, err
 	}
 
-	return executeHTTPRequest(ctx, upload, req, check)
+	return executeHTTPRequest(ctx, client, req, check)
 }
 
 // newUploadRequest creates a new h.Request for uploading.
@@ -306,37 +362,61 @@ func newUploadRequest(ctx *context.Context, method, target, username, secret str
 	return req, err
 }
 
-func getHTTPClient(upload *config.Upload) (*h.Client, error) {
-	if upload.TrustedCerts == "" {
-		return h.DefaultClient, nil
+// getHTTPClient returns the client used for all requests of the given upload,
+// configured with its trusted certificates, client certificate and oauth2
+// auth.
+func getHTTPClient(ctx *context.Context, upload *config.Upload, kind string) (*h.Client, error) {
+	client := h.DefaultClient
+	if upload.TrustedCerts != "" || upload.Auth.ClientCert != "" {
+		tlsConfig := &tls.Config{} // nolint: gosec
+		if upload.TrustedCerts != "" {
+			pool, err := x509.SystemCertPool()
+			if err != nil {
+				if runtime.GOOS == "windows" {
+					// on windows ignore errors until golang issues #16736 & #18609 get fixed
+					pool = x509.NewCertPool()
+				} else {
+					return nil, err
+				}
+			}
+			pool.AppendCertsFromPEM([]byte(upload.TrustedCerts)) // already validated certs checked by CheckConfig
+			tlsConfig.RootCAs = pool
+		}
+		if upload.Auth.ClientCert != "" {
+			cert, err := tls.LoadX509KeyPair(upload.Auth.ClientCert, upload.Auth.ClientKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		client = &h.Client{
+			Transport: &h.Transport{
+				Proxy:           h.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		}
 	}
-	pool, err := x509.SystemCertPool()
-	if err != nil {
-		if runtime.GOOS == "windows" {
-			// on windows ignore errors until golang issues #16736 & #18609 get fixed
-			pool = x509.NewCertPool()
-		} else {
-			return nil, err
+	if upload.Auth.Type == AuthOAuth2ClientCredentials {
+		return oauth2Client(ctx, upload, kind, client)
+	}
+	return client, nil
+}
+
+// redactHeaders returns a copy of the given headers without the credentials,
+// so they can be logged.
+func redactHeaders(headers h.Header) h.Header {
+	result := headers.Clone()
+	for _, name := range []string{"Authorization", "Proxy-Authorization"} {
+		if result.Get(name) != "" {
+			result.Set(name, "[REDACTED]")
 		}
 	}
-	pool.AppendCertsFromPEM([]byte(upload.TrustedCerts)) // already validated certs checked by CheckConfig
-	return &h.Client{
-		Transport: &h.Transport{
-			Proxy: h.ProxyFromEnvironment,
-			TLSClientConfig: &tls.Config{ // nolint: gosec
-				RootCAs: pool,
-			},
-		},
-	}, nil
+	return result
 }
 
 // executeHTTPRequest processes the http call with respect of context ctx.
-func executeHTTPRequest(ctx *context.Context, upload *config.Upload, req *h.Request, check ResponseChecker) (*h.Response, error) {
-	client, err := getHTTPClient(upload)
-	if err != nil {
-		return nil, err
-	}
-	log.Debugf("executing request: %s %s (headers: %v)", req.Method, req.URL, req.Header)
+func executeHTTPRequest(ctx *context.Context, client *h.Client, req *h.Request, check ResponseChecker) (*h.Response, error) {
+	log.Debugf("executing request: %s %s (headers: %v)", req.Method, req.URL, redactHeaders(req.Header))
 	resp, err := client.Do(req)
 	if err != nil {
 		// If we got an error, and the context has been canceled,
@@ -364,19 +444,18 @@ func executeHTTPRequest(ctx *context.Context, upload *config.Upload, req *h.Requ
 // resolveTargetTemplate returns the resolved target template with replaced variables
 // Those variables can be replaced by the given context, goos, goarch, goarm and more.
 func resolveTargetTemplate(ctx *context.Context, upload *config.Upload, artifact *artifact.Artifact) (string, error) {
-	replacements := map[string]string{}
-	if upload.Mode == ModeBinary {
-		// TODO: multiple archives here
-		replacements = ctx.Config.Archives[0].Replacements
-	}
-	return tmpl.New(ctx).
-		WithArtifact(artifact, replacements).
-		Apply(upload.Target)
+	return resolveTemplate(ctx, upload, artifact, upload.Target)
 }
 
 // resolveHeaderTemplate returns the resolved custom header template with replaced variables
 // Those variables can be replaced by the given context, goos, goarch, goarm and more.
 func resolveHeaderTemplate(ctx *context.Context, upload *config.Upload, artifact *artifact.Artifact, headerValue string) (string, error) {
+	return resolveTemplate(ctx, upload, artifact, headerValue)
+}
+
+// resolveTemplate applies the given template with the fields of the given
+// artifact.
+func resolveTemplate(ctx *context.Context, upload *config.Upload, artifact *artifact.Artifact, s string) (string, error) {
 	replacements := map[string]string{}
 	if upload.Mode == ModeBinary {
 		// TODO: multiple archives here
@@ -384,5 +463,5 @@ func resolveHeaderTemplate(ctx *context.Context, upload *config.Upload, artifact
 	}
 	return tmpl.New(ctx).
 		WithArtifact(artifact, replacements).
-		Apply(headerValue)
+		Apply(s)
 }
@@ -54,6 +54,20 @@ func TestAssetOpenDefault(t *testing.T) {
 	}
 }
 
+func TestRedactHeaders(t *testing.T) {
+	headers := h.Header{}
+	headers.Set("Authorization", "Bearer secret")
+	headers.Set("Proxy-Authorization", "Basic secret")
+	headers.Set("X-Checksum", "abc")
+	require.Equal(t, h.Header{
+		"Authorization":       []string{"[REDACTED]"},
+		"Proxy-Authorization": []string{"[REDACTED]"},
+		"X-Checksum":          []string{"abc"},
+	}, redactHeaders(headers))
+	require.Equal(t, "Bearer secret", headers.Get("Authorization"))
+	require.Equal(t, h.Header{}, redactHeaders(h.Header{}))
+}
+
 func TestDefaults(t *testing.T) {
 	type args struct {
 		uploads []config.Upload
@@ -79,6 +93,19 @@ func TestDefaults(t *testing.T) {
 	}
 }
 
+func TestDefaultsBodyFormat(t *testing.T) {
+	uploads := []config.Upload{
+		{Name: "a"},
+		{Name: "b", BodyFormat: BodyFormatMultipart},
+		{Name: "c", BodyFormat: BodyFormatMultipart, FileField: "asset"},
+	}
+	require.NoError(t, Defaults(uploads))
+	require.Equal(t, BodyFormatRaw, uploads[0].BodyFormat)
+	require.Empty(t, uploads[0].FileField)
+	require.Equal(t, "file", uploads[1].FileField)
+	require.Equal(t, "asset", uploads[2].FileField)
+}
+
 func TestCheckConfig(t *testing.T) {
 	ctx := context.New(config.Project{ProjectName: "blah"})
 	ctx.Env["TEST_A_SECRET"] = "x"
@@ -101,6 +128,8 @@ func TestCheckConfig(t *testing.T) {
 		{"mode missing", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe"}, "test"}, true},
 		{"mode invalid", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: "blabla"}, "test"}, true},
 		{"cert invalid", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: ModeBinary, TrustedCerts: "bad cert!"}, "test"}, true},
+		{"body format multipart", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: ModeArchive, BodyFormat: BodyFormatMultipart}, "test"}, false},
+		{"body format invalid", args{ctx, &config.Upload{Name: "a", Target: "http://blabla", Username: "pepe", Mode: ModeArchive, BodyFormat: "json"}, "test"}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -264,6 +293,17 @@ func TestUpload(t *testing.T) {
 		})
 	}
 
+	repoFile := filepath.Join(folder, "Release")
+	require.NoError(t, os.WriteFile(repoFile, []byte("lorem ipsum"), 0o644))
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "dists/stable/Release",
+		Path: repoFile,
+		Type: artifact.RepositoryFile,
+		Extra: map[string]interface{}{
+			"ID": "repo",
+		},
+	})
+
 	tests := []struct {
 		name         string
 		tryPlain     bool
@@ -367,6 +407,43 @@ func TestUpload(t *testing.T) {
 				check{"/blah/2.1.0/a.tar", "u1", "x", content, map[string]string{}},
 			),
 		},
+		{
+			"archive_with_repository_ids", true, true, false, false,
+			func(s *httptest.Server) (*context.Context, config.Upload) {
+				return ctx, config.Upload{
+					Mode:         ModeArchive,
+					Name:         "a",
+					Target:       s.URL + "/{{.ProjectName}}/{{.Version}}/",
+					Username:     "u1",
+					TrustedCerts: cert(s),
+					IDs:          []string{"foo"},
+					Repositories: []string{"repo"},
+				}
+			},
+			checks(
+				check{"/blah/2.1.0/a.deb", "u1", "x", content, map[string]string{}},
+				check{"/blah/2.1.0/a.tar", "u1", "x", content, map[string]string{}},
+				check{"/blah/2.1.0/dists/stable/Release", "u1", "x", content, map[string]string{}},
+			),
+		},
+		{
+			"archive_with_repositories_only", true, true, false, false,
+			func(s *httptest.Server) (*context.Context, config.Upload) {
+				return ctx, config.Upload{
+					Mode:         ModeArchive,
+					Name:         "a",
+					Target:       s.URL + "/{{.ProjectName}}/{{.Version}}/",
+					Username:     "u1",
+					TrustedCerts: cert(s),
+					Repositories: []string{"repo"},
+				}
+			},
+			checks(
+				check{"/blah/2.1.0/a.deb", "u1", "x", content, map[string]string{}},
+				check{"/blah/2.1.0/a.tar", "u1", "x", content, map[string]string{}},
+				check{"/blah/2.1.0/dists/stable/Release", "u1", "x", content, map[string]string{}},
+			),
+		},
 		{
 			"binary", true, true, false, false,
 			func(s *httptest.Server) (*context.Context, config.Upload) {
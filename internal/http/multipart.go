@@ -0,0 +1,75 @@
+package http
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"sort"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// multipartReadCloser reads the multipart body and closes the underlying
+// asset.
+type multipartReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// newMultipartAsset wraps the given asset in a multipart/form-data body, with
+// the configured form fields followed by the asset file, and returns it with
+// its content type.
+// The asset is still streamed, and the body size is known beforehand, so the
+// request keeps its content length.
+func newMultipartAsset(ctx *context.Context, upload *config.Upload, artifact *artifact.Artifact, a *asset) (*asset, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	// sort the fields so the body is always the same
+	names := make([]string, 0, len(upload.FormFields))
+	for name := range upload.FormFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fieldName, err := resolveTemplate(ctx, upload, artifact, name)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to resolve form field name %q: %w", name, err)
+		}
+		value, err := resolveTemplate(ctx, upload, artifact, upload.FormFields[name])
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to resolve form field %q: %w", name, err)
+		}
+		if err := w.WriteField(fieldName, value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	fileField, err := resolveTemplate(ctx, upload, artifact, upload.FileField)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve file_field: %w", err)
+	}
+	if _, err := w.CreateFormFile(fileField, artifact.Name); err != nil {
+		return nil, "", err
+	}
+	prefix := buf.Len()
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	body := buf.Bytes()
+
+	return &asset{
+		ReadCloser: multipartReadCloser{
+			Reader: io.MultiReader(
+				bytes.NewReader(body[:prefix]),
+				a.ReadCloser,
+				bytes.NewReader(body[prefix:]),
+			),
+			Closer: a.ReadCloser,
+		},
+		Size: int64(len(body)) + a.Size,
+	}, w.FormDataContentType(), nil
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	h "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMultipartAsset(t *testing.T) {
+	ctx := context.New(config.Project{ProjectName: "blah"})
+	ctx.Version = "2.1.0"
+	content := "lorem ipsum"
+	a, contentType, err := newMultipartAsset(ctx, &config.Upload{
+		FileField: "{{ .Os }}_file",
+		FormFields: map[string]string{
+			"version":            "{{ .Version }}",
+			"{{ .ProjectName }}": "{{ .ArtifactName }}",
+		},
+	}, &artifact.Artifact{
+		Name: "a.tar.gz",
+		Goos: "linux",
+	}, &asset{
+		ReadCloser: io.NopCloser(strings.NewReader(content)),
+		Size:       int64(len(content)),
+	})
+	require.NoError(t, err)
+	defer a.ReadCloser.Close()
+
+	body, err := io.ReadAll(a.ReadCloser)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(body)), a.Size)
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	require.Equal(t, "multipart/form-data", mediaType)
+
+	r := multipart.NewReader(strings.NewReader(string(body)), params["boundary"])
+	var parts []string
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		bts, err := io.ReadAll(part)
+		require.NoError(t, err)
+		parts = append(parts, fmt.Sprintf("%s:%s:%s", part.FormName(), part.FileName(), bts))
+	}
+	require.Equal(t, []string{
+		"version::2.1.0",
+		"blah::a.tar.gz",
+		"linux_file:a.tar.gz:lorem ipsum",
+	}, parts)
+}
+
+func TestNewMultipartAssetInvalidTemplates(t *testing.T) {
+	ctx := context.New(config.Project{})
+	for name, upload := range map[string]*config.Upload{
+		"failed to resolve form field name": {FormFields: map[string]string{"{{ .Nope }}": "a"}},
+		"failed to resolve form field":      {FormFields: map[string]string{"a": "{{ .Nope }}"}},
+		"failed to resolve file_field":      {FileField: "{{ .Nope }}"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := newMultipartAsset(ctx, upload, &artifact.Artifact{Name: "a"}, &asset{
+				ReadCloser: io.NopCloser(strings.NewReader("")),
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), name)
+		})
+	}
+}
+
+func TestUploadMultipart(t *testing.T) {
+	type form struct {
+		path     string
+		fields   map[string]string
+		filename string
+		content  string
+		checksum string
+	}
+	var forms []form
+	var m sync.Mutex
+	srv := httptest.NewServer(h.HandlerFunc(func(w h.ResponseWriter, r *h.Request) {
+		if err := r.ParseMultipartForm(1024); err != nil {
+			w.WriteHeader(h.StatusBadRequest)
+			return
+		}
+		f, header, err := r.FormFile("upload")
+		if err != nil {
+			w.WriteHeader(h.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+		bts, err := io.ReadAll(f)
+		if err != nil {
+			w.WriteHeader(h.StatusInternalServerError)
+			return
+		}
+		fields := map[string]string{}
+		for name, values := range r.MultipartForm.Value {
+			fields[name] = values[0]
+		}
+		m.Lock()
+		forms = append(forms, form{
+			path:     r.URL.Path,
+			fields:   fields,
+			filename: header.Filename,
+			content:  string(bts),
+			checksum: r.Header.Get("-x-sha256"),
+		})
+		m.Unlock()
+		w.WriteHeader(h.StatusCreated)
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "a.tar")
+	require.NoError(t, os.WriteFile(file, []byte("lorem ipsum"), 0o644))
+	ctx := context.New(config.Project{ProjectName: "blah"})
+	ctx.Version = "2.1.0"
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   "a.tar",
+		Goos:   "linux",
+		Goarch: "amd64",
+		Path:   file,
+		Type:   artifact.UploadableArchive,
+	})
+
+	uploads := []config.Upload{
+		{
+			Name:           "a",
+			Method:         h.MethodPost,
+			Target:         srv.URL + "/upload",
+			BodyFormat:     BodyFormatMultipart,
+			FileField:      "upload",
+			ChecksumHeader: "-x-sha256",
+			FormFields: map[string]string{
+				"version": "{{ .Version }}",
+				"arch":    "{{ .Arch }}",
+			},
+		},
+	}
+	require.NoError(t, Defaults(uploads))
+	require.NoError(t, Upload(ctx, uploads, "test", func(r *h.Response) error {
+		if r.StatusCode != h.StatusCreated {
+			return fmt.Errorf("unexpected http status code: %v", r.StatusCode)
+		}
+		return nil
+	}))
+	require.Equal(t, []form{
+		{
+			path: "/upload/a.tar",
+			fields: map[string]string{
+				"version": "2.1.0",
+				"arch":    "amd64",
+			},
+			filename: "a.tar",
+			content:  "lorem ipsum",
+			checksum: "5e2bf57d3f40c4b6df69daf1936cb766f832374b4fc0259a7cbff06e2f70f269",
+		},
+	}, forms)
+}
@@ -0,0 +1,186 @@
+package linux
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/ulikunitz/xz"
+)
+
+// Deb is the information read from a debian package.
+type Deb struct {
+	Control Paragraph
+	Files   []DebFile
+}
+
+// DebFile is a file inside the data archive of a debian package.
+type DebFile struct {
+	Path     string
+	Mode     os.FileMode
+	Size     int64
+	Owner    string
+	Group    string
+	Linkname string
+}
+
+// ReadDeb reads the given .deb file.
+func ReadDeb(filename string) (*Deb, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deb: %w", err)
+	}
+	defer f.Close()
+
+	var deb Deb
+	if err := readAr(f, func(name string, r io.Reader) error {
+		switch {
+		case strings.HasPrefix(name, "control.tar"):
+			control, err := readControl(name, r)
+			if err != nil {
+				return err
+			}
+			deb.Control = control
+		case strings.HasPrefix(name, "data.tar"):
+			files, err := readData(name, r)
+			if err != nil {
+				return err
+			}
+			deb.Files = files
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to read deb: %s: %w", filename, err)
+	}
+	if len(deb.Control) == 0 {
+		return nil, fmt.Errorf("failed to read deb: %s: control file not found", filename)
+	}
+	return &deb, nil
+}
+
+func readControl(name string, r io.Reader) (Paragraph, error) {
+	tr, err := tarReader(name, r)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if path.Clean(hdr.Name) != "control" {
+			continue
+		}
+		paragraphs, err := ParseParagraphs(tr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid control file: %w", err)
+		}
+		if len(paragraphs) != 1 {
+			return nil, fmt.Errorf("invalid control file: expected 1 paragraph, got %d", len(paragraphs))
+		}
+		return paragraphs[0], nil
+	}
+}
+
+func readData(name string, r io.Reader) ([]DebFile, error) {
+	tr, err := tarReader(name, r)
+	if err != nil {
+		return nil, err
+	}
+	var files []DebFile
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid data archive: %w", err)
+		}
+		files = append(files, DebFile{
+			Path:     path.Clean("/" + hdr.Name),
+			Mode:     hdr.FileInfo().Mode(),
+			Size:     hdr.Size,
+			Owner:    hdr.Uname,
+			Group:    hdr.Gname,
+			Linkname: hdr.Linkname,
+		})
+	}
+}
+
+// tarReader returns a tar reader for the given ar member, decompressing it
+// according to its extension.
+func tarReader(name string, r io.Reader) (*tar.Reader, error) {
+	switch path.Ext(name) {
+	case ".tar":
+		return tar.NewReader(r), nil
+	case ".gz":
+		gr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return tar.NewReader(gr), nil
+	case ".xz":
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return tar.NewReader(xr), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression: %s", name)
+	}
+}
+
+const (
+	arMagic      = "!<arch>\n"
+	arHeaderSize = 60
+)
+
+// readAr calls fn for every member of the given ar archive.
+func readAr(r io.Reader, fn func(name string, r io.Reader) error) error {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(arMagic))
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != arMagic {
+		return errors.New("not an ar archive")
+	}
+	header := make([]byte, arHeaderSize)
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("invalid ar header: %w", err)
+		}
+		if !bytes.Equal(header[58:60], []byte("`\n")) {
+			return errors.New("invalid ar header")
+		}
+		name := strings.TrimSuffix(strings.TrimSpace(string(header[0:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(header[48:58])), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid ar header: %w", err)
+		}
+		member := io.LimitReader(br, size)
+		if err := fn(name, member); err != nil {
+			return err
+		}
+		// skip whatever was not read plus the padding to an even offset.
+		if _, err := io.Copy(io.Discard, member); err != nil {
+			return err
+		}
+		if size%2 != 0 {
+			if _, err := br.Discard(1); err != nil && !errors.Is(err, io.EOF) {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package linux
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Field is a single field of a deb822 paragraph.
+// Multi-line values keep their continuation lines, including the leading
+// whitespace, separated by new lines.
+type Field struct {
+	Name  string
+	Value string
+}
+
+// Paragraph is a deb822 paragraph, as found in debian control files,
+// Packages and Release files.
+// Fields are kept in the order they were added.
+type Paragraph []Field
+
+// Get returns the value of the given field, or an empty string if it is not
+// present.
+// Field names are case-insensitive.
+func (p Paragraph) Get(name string) string {
+	for _, f := range p {
+		if strings.EqualFold(f.Name, name) {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// Set replaces the value of the given field, adding it to the end of the
+// paragraph if not present.
+func (p *Paragraph) Set(name, value string) {
+	for i, f := range *p {
+		if strings.EqualFold(f.Name, name) {
+			(*p)[i].Value = value
+			return
+		}
+	}
+	*p = append(*p, Field{Name: name, Value: value})
+}
+
+func (p Paragraph) String() string {
+	var sb strings.Builder
+	for _, f := range p {
+		sb.WriteString(f.Name)
+		sb.WriteString(":")
+		if !strings.HasPrefix(f.Value, "\n") {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(f.Value)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// ParseParagraphs parses all the deb822 paragraphs in the given reader.
+func ParseParagraphs(r io.Reader) ([]Paragraph, error) {
+	var result []Paragraph
+	var current Paragraph
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			if len(current) > 0 {
+				result = append(result, current)
+				current = nil
+			}
+		case strings.HasPrefix(line, "#"):
+			continue
+		case line[0] == ' ' || line[0] == '\t':
+			if len(current) == 0 {
+				return nil, fmt.Errorf("invalid continuation line: %q", line)
+			}
+			current[len(current)-1].Value += "\n" + line
+		default:
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) != 2 {
+				return nil, fmt.Errorf("invalid field: %q", line)
+			}
+			current = append(current, Field{
+				Name:  parts[0],
+				Value: strings.TrimSpace(parts[1]),
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(current) > 0 {
+		result = append(result, current)
+	}
+	return result, nil
+}
@@ -0,0 +1,165 @@
+package linux
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goreleaser/nfpm/v2"
+	_ "github.com/goreleaser/nfpm/v2/deb" // blank import to register the format
+	"github.com/goreleaser/nfpm/v2/files"
+	_ "github.com/goreleaser/nfpm/v2/rpm" // blank import to register the format
+	"github.com/stretchr/testify/require"
+)
+
+func testPackage(t *testing.T, format string, depends ...string) string {
+	t.Helper()
+	info := nfpm.WithDefaults(&nfpm.Info{
+		Name:        "mybin",
+		Arch:        "amd64",
+		Platform:    "linux",
+		Version:     "1.2.3",
+		Maintainer:  "Foo Bar <foo@bar>",
+		Description: "My binary.\nIt does things.",
+		License:     "MIT",
+		Vendor:      "GoReleaser",
+		Homepage:    "https://goreleaser.com",
+		Overridables: nfpm.Overridables{
+			Depends:   depends,
+			Conflicts: []string{"notmybin"},
+			Contents: files.Contents{
+				{
+					Source:      "./testdata/mybin",
+					Destination: "/usr/bin/mybin",
+					FileInfo: &files.ContentFileInfo{
+						Mode: 0o755,
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, nfpm.Validate(info))
+	packager, err := nfpm.Get(format)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "mybin."+format)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, packager.Package(info, f))
+	require.NoError(t, f.Close())
+	return path
+}
+
+func TestReadDeb(t *testing.T) {
+	deb, err := ReadDeb(testPackage(t, "deb", "libc6 (>= 2.17)", "git"))
+	require.NoError(t, err)
+	require.Equal(t, "mybin", deb.Control.Get("Package"))
+	require.Equal(t, "1.2.3", deb.Control.Get("version"))
+	require.Equal(t, "amd64", deb.Control.Get("Architecture"))
+	require.Equal(t, "libc6 (>= 2.17), git", deb.Control.Get("Depends"))
+	require.Equal(t, "My binary.\n  It does things.", deb.Control.Get("Description"))
+
+	var found bool
+	for _, f := range deb.Files {
+		if f.Path != "/usr/bin/mybin" {
+			continue
+		}
+		found = true
+		require.Equal(t, os.FileMode(0o755), f.Mode)
+	}
+	require.True(t, found, "binary not found in %v", deb.Files)
+}
+
+func TestReadDebInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.deb")
+	require.NoError(t, os.WriteFile(path, []byte("nope"), 0o644))
+	_, err := ReadDeb(path)
+	require.EqualError(t, err, "failed to read deb: "+path+": not an ar archive")
+
+	_, err = ReadDeb(filepath.Join(t.TempDir(), "nope.deb"))
+	require.Error(t, err)
+}
+
+func TestReadRPM(t *testing.T) {
+	path := testPackage(t, "rpm", "libc6 >= 2.17", "git")
+	rpm, err := ReadRPM(path)
+	require.NoError(t, err)
+	require.Equal(t, "mybin", rpm.Name)
+	require.Equal(t, "1.2.3", rpm.Version)
+	require.Equal(t, "1", rpm.Release)
+	require.Equal(t, "x86_64", rpm.Arch)
+	require.Equal(t, "MIT", rpm.License)
+	require.Equal(t, "GoReleaser", rpm.Vendor)
+	require.Equal(t, "https://goreleaser.com", rpm.URL)
+	require.Equal(t, "My binary.\nIt does things.", rpm.Description)
+	require.Contains(t, rpm.Requires, RPMRelation{Name: "git"})
+	require.Contains(t, rpm.Requires, RPMRelation{Name: "libc6", Flags: "GE", Epoch: "0", Version: "2.17"})
+	require.Equal(t, []RPMRelation{{Name: "notmybin"}}, rpm.Conflicts)
+	for _, req := range rpm.Requires {
+		require.False(t, strings.HasPrefix(req.Name, "rpmlib("), req.Name)
+	}
+
+	var found bool
+	for _, f := range rpm.Files {
+		if f.Path == "/usr/bin/mybin" {
+			found = true
+			require.Equal(t, os.FileMode(0o755), f.Mode)
+		}
+	}
+	require.True(t, found, "/usr/bin/mybin not found in %v", rpm.Files)
+
+	require.Greater(t, rpm.HeaderStart, int64(96))
+	require.Greater(t, rpm.HeaderEnd, rpm.HeaderStart)
+	bts, err := os.ReadFile(path)
+	require.NoError(t, err)
+	// main header magic
+	require.Equal(t, []byte{0x8e, 0xad, 0xe8, 0x01}, bts[rpm.HeaderStart:rpm.HeaderStart+4])
+}
+
+func TestReadRPMInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.rpm")
+	require.NoError(t, os.WriteFile(path, []byte("nope"), 0o644))
+	_, err := ReadRPM(path)
+	require.Error(t, err)
+}
+
+func TestSplitEVR(t *testing.T) {
+	for evr, expected := range map[string][3]string{
+		"":           {"", "", ""},
+		"1.0":        {"0", "1.0", ""},
+		"1.0-1":      {"0", "1.0", "1"},
+		"2:1.0-1.el": {"2", "1.0", "1.el"},
+	} {
+		e, v, r := splitEVR(evr)
+		require.Equal(t, expected, [3]string{e, v, r}, evr)
+	}
+}
+
+func TestParseParagraphs(t *testing.T) {
+	paragraphs, err := ParseParagraphs(strings.NewReader(`# comment
+Package: foo
+Description: short
+ long line 1
+ .
+ long line 2
+
+Package: bar
+MD5Sum:
+ abc 123 main/Packages
+`))
+	require.NoError(t, err)
+	require.Len(t, paragraphs, 2)
+	require.Equal(t, "foo", paragraphs[0].Get("package"))
+	require.Equal(t, "short\n long line 1\n .\n long line 2", paragraphs[0].Get("Description"))
+	require.Equal(t, "\n abc 123 main/Packages", paragraphs[1].Get("MD5Sum"))
+	require.Equal(t, "Package: bar\nMD5Sum:\n abc 123 main/Packages\n", paragraphs[1].String())
+
+	paragraphs[1].Set("Package", "baz")
+	paragraphs[1].Set("Size", "10")
+	require.Equal(t, "Package: baz\nMD5Sum:\n abc 123 main/Packages\nSize: 10\n", paragraphs[1].String())
+
+	_, err = ParseParagraphs(strings.NewReader(" nope\n"))
+	require.Error(t, err)
+	_, err = ParseParagraphs(strings.NewReader("nope\n"))
+	require.Error(t, err)
+}
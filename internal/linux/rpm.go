@@ -0,0 +1,298 @@
+package linux
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	rpmutils "github.com/sassoftware/go-rpmutils"
+)
+
+// RPM is the information read from the headers of a rpm package.
+type RPM struct {
+	Name          string
+	Epoch         int
+	Version       string
+	Release       string
+	Arch          string
+	Summary       string
+	Description   string
+	License       string
+	Vendor        string
+	URL           string
+	Group         string
+	Packager      string
+	BuildHost     string
+	SourceRPM     string
+	BuildTime     int
+	InstalledSize int64
+	ArchiveSize   int64
+	Provides      []RPMRelation
+	Requires      []RPMRelation
+	Conflicts     []RPMRelation
+	Obsoletes     []RPMRelation
+	Files         []RPMFile
+
+	// HeaderStart and HeaderEnd are the byte offsets of the main header in
+	// the file.
+	HeaderStart int64
+	HeaderEnd   int64
+}
+
+// RPMRelation is a rpm dependency, e.g. a requires or provides entry.
+type RPMRelation struct {
+	Name    string
+	Flags   string // EQ, LT, LE, GT, GE or empty
+	Epoch   string
+	Version string
+	Release string
+}
+
+// RPMFile is a file inside a rpm package.
+type RPMFile struct {
+	Path     string
+	Mode     os.FileMode
+	Size     int64
+	Owner    string
+	Group    string
+	Linkname string
+	Flags    int
+}
+
+// tags not defined by rpmutils.
+const (
+	rpmTagConflictFlags   = 1053
+	rpmTagConflictName    = 1054
+	rpmTagConflictVersion = 1055
+
+	rpmSenseRPMLib = 1 << 24
+)
+
+// ReadRPM reads the headers of the given .rpm file.
+func ReadRPM(filename string) (*RPM, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rpm: %w", err)
+	}
+	defer f.Close()
+
+	start, err := rpmHeaderStart(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rpm: %s: %w", filename, err)
+	}
+
+	cr := &countingReader{r: f}
+	hdr, err := rpmutils.ReadHeader(cr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rpm: %s: %w", filename, err)
+	}
+
+	rpm := RPM{
+		HeaderStart: start,
+		HeaderEnd:   cr.n,
+	}
+	for tag, s := range map[int]*string{
+		rpmutils.NAME:        &rpm.Name,
+		rpmutils.VERSION:     &rpm.Version,
+		rpmutils.RELEASE:     &rpm.Release,
+		rpmutils.ARCH:        &rpm.Arch,
+		rpmutils.SUMMARY:     &rpm.Summary,
+		rpmutils.DESCRIPTION: &rpm.Description,
+		rpmutils.LICENSE:     &rpm.License,
+		rpmutils.VENDOR:      &rpm.Vendor,
+		rpmutils.URL:         &rpm.URL,
+		rpmutils.GROUP:       &rpm.Group,
+		rpmutils.PACKAGER:    &rpm.Packager,
+		rpmutils.BUILDHOST:   &rpm.BuildHost,
+		rpmutils.SOURCERPM:   &rpm.SourceRPM,
+	} {
+		if !hdr.HasTag(tag) {
+			continue
+		}
+		v, err := hdr.GetString(tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rpm: %s: tag %d: %w", filename, tag, err)
+		}
+		*s = v
+	}
+	if rpm.Name == "" || rpm.Version == "" || rpm.Arch == "" {
+		return nil, fmt.Errorf("failed to read rpm: %s: missing name, version or arch", filename)
+	}
+
+	for tag, i := range map[int]*int{
+		rpmutils.EPOCH:     &rpm.Epoch,
+		rpmutils.BUILDTIME: &rpm.BuildTime,
+	} {
+		if !hdr.HasTag(tag) {
+			continue
+		}
+		v, err := hdr.GetInt(tag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rpm: %s: tag %d: %w", filename, tag, err)
+		}
+		*i = v
+	}
+
+	if size, err := hdr.InstalledSize(); err == nil {
+		rpm.InstalledSize = size
+	}
+	if size, err := hdr.PayloadSize(); err == nil {
+		rpm.ArchiveSize = size
+	}
+
+	for _, rel := range []struct {
+		result                   *[]RPMRelation
+		nameTag, flagTag, verTag int
+	}{
+		{&rpm.Provides, rpmutils.PROVIDENAME, rpmutils.PROVIDEFLAGS, rpmutils.PROVIDEVERSION},
+		{&rpm.Requires, rpmutils.REQUIRENAME, rpmutils.REQUIREFLAGS, rpmutils.REQUIREVERSION},
+		{&rpm.Conflicts, rpmTagConflictName, rpmTagConflictFlags, rpmTagConflictVersion},
+		{&rpm.Obsoletes, rpmutils.OBSOLETENAME, rpmutils.OBSOLETEFLAGS, rpmutils.OBSOLETEVERSION},
+	} {
+		relations, err := readRelations(hdr, rel.nameTag, rel.flagTag, rel.verTag)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rpm: %s: %w", filename, err)
+		}
+		*rel.result = relations
+	}
+
+	files, err := hdr.GetFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rpm: %s: %w", filename, err)
+	}
+	for _, file := range files {
+		rpm.Files = append(rpm.Files, RPMFile{
+			Path:     file.Name(),
+			Mode:     rpmFileMode(file.Mode()),
+			Size:     file.Size(),
+			Owner:    file.UserName(),
+			Group:    file.GroupName(),
+			Linkname: file.Linkname(),
+			Flags:    file.Flags(),
+		})
+	}
+
+	return &rpm, nil
+}
+
+func readRelations(hdr *rpmutils.RpmHeader, nameTag, flagTag, verTag int) ([]RPMRelation, error) {
+	if !hdr.HasTag(nameTag) {
+		return nil, nil
+	}
+	names, err := hdr.GetStrings(nameTag)
+	if err != nil {
+		return nil, err
+	}
+	flags := make([]int, len(names))
+	if hdr.HasTag(flagTag) {
+		if flags, err = hdr.GetInts(flagTag); err != nil {
+			return nil, err
+		}
+	}
+	versions := make([]string, len(names))
+	if hdr.HasTag(verTag) {
+		if versions, err = hdr.GetStrings(verTag); err != nil {
+			return nil, err
+		}
+	}
+	if len(flags) != len(names) || len(versions) != len(names) {
+		return nil, fmt.Errorf("tag %d: inconsistent dependency entries", nameTag)
+	}
+
+	// nolint:prealloc
+	var result []RPMRelation
+	for i, name := range names {
+		if flags[i]&rpmSenseRPMLib != 0 || strings.HasPrefix(name, "rpmlib(") {
+			continue
+		}
+		rel := RPMRelation{
+			Name:  name,
+			Flags: rpmSenseFlags(flags[i]),
+		}
+		rel.Epoch, rel.Version, rel.Release = splitEVR(versions[i])
+		result = append(result, rel)
+	}
+	return result, nil
+}
+
+func rpmSenseFlags(flags int) string {
+	const (
+		less    = 1 << 1
+		greater = 1 << 2
+		equal   = 1 << 3
+	)
+	switch flags & (less | greater | equal) {
+	case equal:
+		return "EQ"
+	case less:
+		return "LT"
+	case less | equal:
+		return "LE"
+	case greater:
+		return "GT"
+	case greater | equal:
+		return "GE"
+	default:
+		return ""
+	}
+}
+
+// splitEVR splits a [epoch:]version[-release] string.
+func splitEVR(evr string) (epoch, version, release string) {
+	if evr == "" {
+		return "", "", ""
+	}
+	epoch = "0"
+	if i := strings.Index(evr, ":"); i >= 0 {
+		epoch, evr = evr[:i], evr[i+1:]
+	}
+	version = evr
+	if i := strings.LastIndex(evr, "-"); i >= 0 {
+		version, release = evr[:i], evr[i+1:]
+	}
+	return epoch, version, release
+}
+
+// rpmFileMode converts a rpm (unix) file mode to an os.FileMode.
+func rpmFileMode(mode int) os.FileMode {
+	const (
+		typeMask = 0o170000
+		dir      = 0o040000
+		symlink  = 0o120000
+	)
+	result := os.FileMode(mode & 0o777)
+	switch mode & typeMask {
+	case dir:
+		result |= os.ModeDir
+	case symlink:
+		result |= os.ModeSymlink
+	}
+	return result
+}
+
+// rpmHeaderStart returns the offset in which the main header starts, which is
+// right after the lead and the (8-byte aligned) signature header.
+func rpmHeaderStart(r io.ReaderAt) (int64, error) {
+	const leadSize = 96
+	intro := make([]byte, 16)
+	if _, err := r.ReadAt(intro, leadSize); err != nil {
+		return 0, fmt.Errorf("invalid signature header: %w", err)
+	}
+	entries := int64(binary.BigEndian.Uint32(intro[8:12]))
+	size := int64(binary.BigEndian.Uint32(intro[12:16]))
+	size = (size + 7) / 8 * 8
+	return leadSize + 16 + entries*16 + size, nil
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -5,7 +5,13 @@ import (
 	"fmt"
 
 	"github.com/goreleaser/goreleaser/internal/middleware"
+	"github.com/goreleaser/goreleaser/internal/pipe/discord"
+	"github.com/goreleaser/goreleaser/internal/pipe/mastodon"
+	"github.com/goreleaser/goreleaser/internal/pipe/slack"
+	"github.com/goreleaser/goreleaser/internal/pipe/smtp"
+	"github.com/goreleaser/goreleaser/internal/pipe/telegram"
 	"github.com/goreleaser/goreleaser/internal/pipe/twitter"
+	"github.com/goreleaser/goreleaser/internal/pipe/webhook"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
@@ -25,7 +31,13 @@ type Announcer interface {
 
 // nolint: gochecknoglobals
 var announcers = []Announcer{
-	twitter.Pipe{}, // announce to twitter
+	twitter.Pipe{},  // announce to twitter
+	slack.Pipe{},    // announce to slack
+	discord.Pipe{},  // announce to discord
+	webhook.Pipe{},  // announce to custom webhooks
+	smtp.Pipe{},     // announce by email
+	mastodon.Pipe{}, // announce to mastodon
+	telegram.Pipe{}, // announce to telegram
 }
 
 // Run the pipe.
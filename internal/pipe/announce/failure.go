@@ -0,0 +1,145 @@
+package announce
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/apex/log"
+	"github.com/goreleaser/goreleaser/internal/middleware"
+	"github.com/goreleaser/goreleaser/internal/pipe/smtp"
+	"github.com/goreleaser/goreleaser/internal/pipe/webhook"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/goreleaser/goreleaser/pkg/defaults"
+)
+
+const (
+	defaultFailureMessageTemplate = `{{ .ProjectName }} {{ .Tag }} release failed at {{ .FailedPipe }}: {{ .Error }}`
+	defaultFailureSubjectTemplate = `{{ .ProjectName }} {{ .Tag }} release failed`
+	defaultFailureWebhookTemplate = `{ "message": "{{ jsonescape .Message }}"}`
+)
+
+// Failed announces, if enabled, that the release failed while running the
+// pipe with the given name.
+func Failed(ctx *context.Context, failedPipe string, failure error) error {
+	conf := ctx.Config.Announce.OnFailure
+	if !conf.Enabled || failedPipe == (Pipe{}).String() {
+		return nil
+	}
+
+	selected, err := failureAnnouncers(conf.Announcers)
+	if err != nil {
+		return fmt.Errorf("announce: failed to announce failure: %w", err)
+	}
+
+	if conf.MessageTemplate == "" {
+		conf.MessageTemplate = defaultFailureMessageTemplate
+	}
+	fields := tmpl.Fields{
+		"FailedPipe": failedPipe,
+		"Error":      failure.Error(),
+	}
+	msg, err := tmpl.New(ctx).WithExtraFields(fields).Apply(conf.MessageTemplate)
+	if err != nil {
+		return fmt.Errorf("announce: failed to announce failure: %w", err)
+	}
+
+	// the release may have failed before the defaults were set, and the
+	// announcers configuration is changed to send the failure message, so
+	// it is restored once done.
+	original := ctx.Config.Announce
+	defer func() {
+		ctx.Config.Announce = original
+	}()
+	ctx.Config.Announce.Webhook = append([]config.Webhook{}, original.Webhook...)
+	for _, announcer := range selected {
+		if defaulter, ok := announcer.(defaults.Defaulter); ok {
+			if err := defaulter.Default(ctx); err != nil {
+				return fmt.Errorf("announce: failed to announce failure: %w", err)
+			}
+		}
+	}
+	fields["Message"] = msg
+	if err := useFailureMessage(ctx, fields); err != nil {
+		return fmt.Errorf("announce: failed to announce failure: %w", err)
+	}
+
+	log.WithField("pipe", failedPipe).Info("announcing failure")
+	for _, announcer := range selected {
+		if err := middleware.Logging(
+			announcer.String(),
+			middleware.ErrHandler(announcer.Announce),
+			middleware.ExtraPadding,
+		)(ctx); err != nil {
+			return fmt.Errorf("%s: failed to announce failure: %w", announcer.String(), err)
+		}
+	}
+	return nil
+}
+
+// failureAnnouncers returns the announcers with the given names, or all of
+// them if no names are given.
+func failureAnnouncers(names []string) ([]Announcer, error) {
+	if len(names) == 0 {
+		return announcers, nil
+	}
+	var result []Announcer
+	for _, name := range names {
+		var found bool
+		for _, announcer := range announcers {
+			if announcer.String() == name {
+				result = append(result, announcer)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown announcer: %s", name)
+		}
+	}
+	return result, nil
+}
+
+// useFailureMessage changes the templates of all the announcers so they send
+// the failure message instead.
+// Webhooks render their failure template, or the default failure body if they
+// use the default message template. Webhooks with a custom message template
+// and no failure template are skipped, as their body is a success message.
+func useFailureMessage(ctx *context.Context, fields tmpl.Fields) error {
+	announce := &ctx.Config.Announce
+	msg := fields["Message"].(string)
+	announce.Twitter.MessageTemplate = literal(msg)
+	announce.Slack.MessageTemplate = literal(msg)
+	announce.Slack.Blocks = nil
+	announce.Discord.MessageTemplate = literal(msg)
+	announce.Mastodon.MessageTemplate = literal(msg)
+	announce.Telegram.MessageTemplate = "{{ mdv2escape " + strconv.Quote(msg) + " }}"
+	announce.SMTP.SubjectTemplate = defaultFailureSubjectTemplate
+	announce.SMTP.BodyTemplate = literal(msg)
+	announce.SMTP.BodyFormat = smtp.BodyFormatText
+	hooks := make([]config.Webhook, 0, len(announce.Webhook))
+	for _, hook := range announce.Webhook {
+		body := hook.FailureTemplate
+		if body == "" {
+			if hook.MessageTemplate != "" && hook.MessageTemplate != webhook.DefaultMessageTemplate {
+				log.WithField("webhook", hook.Name).Warn("webhook has a custom message_template but no failure_template, not announcing the failure to it")
+				continue
+			}
+			body = defaultFailureWebhookTemplate
+		}
+		body, err := tmpl.New(ctx).WithExtraFields(fields).Apply(body)
+		if err != nil {
+			return fmt.Errorf("webhook %s: %w", hook.Name, err)
+		}
+		hook.MessageTemplate = literal(body)
+		hooks = append(hooks, hook)
+	}
+	announce.Webhook = hooks
+	return nil
+}
+
+// literal returns a template which outputs the given string as is.
+func literal(s string) string {
+	return "{{ " + strconv.Quote(s) + " }}"
+}
@@ -0,0 +1,150 @@
+package announce
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func failureContext(url string, onFailure config.AnnounceOnFailure) *context.Context {
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Announce: config.Announce{
+			Webhook:   []config.Webhook{{Name: "bot", URL: url}},
+			OnFailure: onFailure,
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	return ctx
+}
+
+func bodiesServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bts, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(bts))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies
+}
+
+func TestFailed(t *testing.T) {
+	srv, bodies := bodiesServer(t)
+	ctx := failureContext(srv.URL, config.AnnounceOnFailure{Enabled: true})
+	require.NoError(t, Failed(ctx, "building binaries", errors.New(`failed to build: "{{ .Foo }}"`)))
+	require.Equal(t, []string{
+		`{ "message": "foo v1.0.0 release failed at building binaries: failed to build: \"{{ .Foo }}\""}`,
+	}, *bodies)
+	require.Equal(t, []config.Webhook{{Name: "bot", URL: srv.URL}}, ctx.Config.Announce.Webhook)
+}
+
+func TestFailedCustomTemplate(t *testing.T) {
+	srv, bodies := bodiesServer(t)
+	ctx := failureContext(srv.URL, config.AnnounceOnFailure{
+		Enabled:         true,
+		MessageTemplate: "{{ .FailedPipe }}|{{ .Error }}",
+	})
+	require.NoError(t, Failed(ctx, "publishing", errors.New("boom")))
+	require.Equal(t, []string{`{ "message": "publishing|boom"}`}, *bodies)
+}
+
+func TestFailedWebhookMessageTemplate(t *testing.T) {
+	srv, bodies := bodiesServer(t)
+	ctx := failureContext(srv.URL, config.AnnounceOnFailure{Enabled: true})
+	ctx.Config.Announce.Webhook = append(ctx.Config.Announce.Webhook, config.Webhook{
+		Name:            "custom",
+		URL:             srv.URL,
+		MessageTemplate: `{ "text": "{{ .Tag }} released" }`,
+	})
+	require.NoError(t, Failed(ctx, "publishing", errors.New("boom")))
+	require.Equal(t, []string{`{ "message": "foo v1.0.0 release failed at publishing: boom"}`}, *bodies)
+	require.Equal(t, `{ "text": "{{ .Tag }} released" }`, ctx.Config.Announce.Webhook[1].MessageTemplate)
+}
+
+func TestFailedWebhookMessageTemplateOnly(t *testing.T) {
+	srv, bodies := bodiesServer(t)
+	ctx := failureContext(srv.URL, config.AnnounceOnFailure{Enabled: true})
+	ctx.Config.Announce.Webhook[0].MessageTemplate = `{ "text": "{{ .Tag }} released" }`
+	require.NoError(t, Failed(ctx, "publishing", errors.New("boom")))
+	require.Empty(t, *bodies)
+}
+
+func TestFailedWebhookFailureTemplate(t *testing.T) {
+	srv, bodies := bodiesServer(t)
+	ctx := failureContext(srv.URL, config.AnnounceOnFailure{Enabled: true})
+	ctx.Config.Announce.Webhook[0].MessageTemplate = `{ "text": "released" }`
+	ctx.Config.Announce.Webhook[0].FailureTemplate = `{ "text": "{{ jsonescape .Message }}", "pipe": "{{ .FailedPipe }}" }`
+	require.NoError(t, Failed(ctx, "publishing", errors.New("boom")))
+	require.Equal(t, []string{`{ "text": "foo v1.0.0 release failed at publishing: boom", "pipe": "publishing" }`}, *bodies)
+}
+
+func TestFailedWebhookInvalidTemplate(t *testing.T) {
+	ctx := failureContext("http://localhost", config.AnnounceOnFailure{Enabled: true})
+	ctx.Config.Announce.Webhook[0].FailureTemplate = "{{ .Foo }"
+	require.EqualError(t, Failed(ctx, "publishing", errors.New("boom")), `announce: failed to announce failure: webhook bot: template: tmpl:1: unexpected "}" in operand`)
+}
+
+func TestFailedDisabled(t *testing.T) {
+	srv, bodies := bodiesServer(t)
+	ctx := failureContext(srv.URL, config.AnnounceOnFailure{})
+	require.NoError(t, Failed(ctx, "publishing", errors.New("boom")))
+	require.Empty(t, *bodies)
+}
+
+func TestFailedAnnouncing(t *testing.T) {
+	srv, bodies := bodiesServer(t)
+	ctx := failureContext(srv.URL, config.AnnounceOnFailure{Enabled: true})
+	require.NoError(t, Failed(ctx, Pipe{}.String(), errors.New("boom")))
+	require.Empty(t, *bodies)
+}
+
+func TestFailedSubset(t *testing.T) {
+	srv, bodies := bodiesServer(t)
+	ctx := failureContext(srv.URL, config.AnnounceOnFailure{
+		Enabled:    true,
+		Announcers: []string{"twitter"},
+	})
+	require.NoError(t, Failed(ctx, "publishing", errors.New("boom")))
+	require.Empty(t, *bodies)
+
+	ctx.Config.Announce.OnFailure.Announcers = []string{"twitter", "webhook"}
+	require.NoError(t, Failed(ctx, "publishing", errors.New("boom")))
+	require.Len(t, *bodies, 1)
+}
+
+func TestFailedUnknownAnnouncer(t *testing.T) {
+	ctx := failureContext("http://localhost", config.AnnounceOnFailure{
+		Enabled:    true,
+		Announcers: []string{"irc"},
+	})
+	require.EqualError(t, Failed(ctx, "publishing", errors.New("boom")), "announce: failed to announce failure: unknown announcer: irc")
+}
+
+func TestFailedInvalidTemplate(t *testing.T) {
+	ctx := failureContext("http://localhost", config.AnnounceOnFailure{
+		Enabled:         true,
+		MessageTemplate: "{{ .Foo }",
+	})
+	require.EqualError(t, Failed(ctx, "publishing", errors.New("boom")), `announce: failed to announce failure: template: tmpl:1: unexpected "}" in operand`)
+}
+
+func TestFailedAnnouncerError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	ctx := failureContext(srv.URL, config.AnnounceOnFailure{Enabled: true})
+	require.EqualError(t, Failed(ctx, "publishing", errors.New("boom")), "webhook: failed to announce failure: announce: failed to announce to webhook bot: unexpected http status code 500: ")
+}
+
+func TestLiteral(t *testing.T) {
+	require.Equal(t, `{{ "a \"{{ b }}\"\n" }}`, literal("a \"{{ b }}\"\n"))
+}
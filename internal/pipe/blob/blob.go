@@ -1,4 +1,4 @@
-// Package blob provides the pipe implementation that uploads files to "blob" providers, such as s3, gcs and azure.
+// Package blob provides the pipe implementation that uploads files to "blob" providers, such as s3, gcs, azure and local directories.
 package blob
 
 import (
@@ -29,6 +29,9 @@ func (Pipe) Default(ctx *context.Context) error {
 		if blob.Folder == "" {
 			blob.Folder = "{{ .ProjectName }}/{{ .Tag }}"
 		}
+		if blob.ContentDisposition == "" {
+			blob.ContentDisposition = "attachment; filename={{ .Filename }}"
+		}
 	}
 	return nil
 }
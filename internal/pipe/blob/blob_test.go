@@ -1,6 +1,9 @@
 package blob
 
 import (
+	"encoding/base64"
+	"errors"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -11,6 +14,8 @@ import (
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 	"github.com/stretchr/testify/require"
+	_ "gocloud.dev/blob/fileblob"
+	"gocloud.dev/secrets/localsecrets"
 )
 
 func TestDescription(t *testing.T) {
@@ -76,11 +81,15 @@ func TestDefaults(t *testing.T) {
 			Provider: "azblob",
 			Folder:   "{{ .ProjectName }}/{{ .Tag }}",
 			IDs:      []string{"foo", "bar"},
+
+			ContentDisposition: "attachment; filename={{ .Filename }}",
 		},
 		{
 			Bucket:   "foobar",
 			Provider: "gcs",
 			Folder:   "{{ .ProjectName }}/{{ .Tag }}",
+
+			ContentDisposition: "attachment; filename={{ .Filename }}",
 		},
 	}, ctx.Config.Blobs)
 }
@@ -304,6 +313,141 @@ func TestURL(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, "gs://foo", url)
 	})
+
+	t.Run("file", func(t *testing.T) {
+		ctx := context.New(config.Project{ProjectName: "foo"})
+		url, err := urlFor(ctx, config.Blob{
+			Bucket:   "./dist/{{ .ProjectName }}",
+			Provider: "file",
+		})
+		require.NoError(t, err)
+		dir, err := filepath.Abs("./dist/foo")
+		require.NoError(t, err)
+		dir = filepath.ToSlash(dir)
+		if !strings.HasPrefix(dir, "/") {
+			dir = "/" + dir
+		}
+		require.Equal(t, "file://"+dir+"?create_dir=true&metadata=skip", url)
+	})
+
+	t.Run("invalid bucket template", func(t *testing.T) {
+		_, err := urlFor(context.New(config.Project{}), config.Blob{
+			Bucket:   "{{ .Nope }",
+			Provider: "file",
+		})
+		require.Error(t, err)
+	})
+}
+
+func TestPipe_PublishFile(t *testing.T) {
+	folder := t.TempDir()
+	tgzpath := filepath.Join(folder, "bin.tar.gz")
+	require.NoError(t, os.WriteFile(tgzpath, []byte("fake\ntargz"), 0o644))
+	mirror := filepath.Join(t.TempDir(), "mirror")
+	stale := filepath.Join(mirror, "testupload", "latest", "bin_0.9.0.tar.gz")
+	require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0o755))
+	require.NoError(t, os.WriteFile(stale, []byte("old"), 0o644))
+
+	ctx := context.New(config.Project{
+		Dist:        folder,
+		ProjectName: "testupload",
+		Blobs: []config.Blob{
+			{
+				Bucket:   mirror,
+				Provider: "file",
+				ExtraFiles: []config.ExtraFile{
+					{Glob: "./testdata/file.golden"},
+				},
+				ContentType:  "{{ if eq .Os \"windows\" }}application/x-msdownload{{ end }}",
+				Repositories: []string{"default"},
+				Index:        config.BlobIndex{Enabled: true},
+				Latest: config.BlobLatest{
+					Folder: "{{ .ProjectName }}/latest",
+					File:   "{{ .ProjectName }}/latest.txt",
+				},
+			},
+		},
+	})
+	ctx.Git = context.GitInfo{CurrentTag: "v1.0.0"}
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Type: artifact.UploadableArchive,
+		Name: "bin.tar.gz",
+		Path: tgzpath,
+	})
+	for _, id := range []string{"default", "other"} {
+		ctx.Artifacts.Add(&artifact.Artifact{
+			Type:  artifact.RepositoryFile,
+			Name:  id + "/dists/stable/Release",
+			Path:  tgzpath,
+			Extra: map[string]interface{}{"ID": id},
+		})
+	}
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Publish(ctx))
+
+	var files []string
+	require.NoError(t, filepath.Walk(mirror, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(mirror, path)
+		files = append(files, filepath.ToSlash(rel))
+		return err
+	}))
+	require.Equal(t, []string{
+		"testupload/latest/bin.tar.gz",
+		"testupload/latest/default/dists/stable/Release",
+		"testupload/latest/file.golden",
+		"testupload/latest/index.html",
+		"testupload/latest/index.json",
+		"testupload/latest.txt",
+		"testupload/v1.0.0/bin.tar.gz",
+		"testupload/v1.0.0/default/dists/stable/Release",
+		"testupload/v1.0.0/file.golden",
+		"testupload/v1.0.0/index.html",
+		"testupload/v1.0.0/index.json",
+	}, files)
+
+	bts, err := os.ReadFile(filepath.Join(mirror, "testupload", "v1.0.0", "bin.tar.gz"))
+	require.NoError(t, err)
+	require.Equal(t, "fake\ntargz", string(bts))
+	bts, err = os.ReadFile(filepath.Join(mirror, "testupload", "latest.txt"))
+	require.NoError(t, err)
+	require.Equal(t, "v1.0.0\n", string(bts))
+}
+
+func TestPipe_PublishLatestFolderContainsRelease(t *testing.T) {
+	folder := t.TempDir()
+	tgzpath := filepath.Join(folder, "bin.tar.gz")
+	require.NoError(t, os.WriteFile(tgzpath, []byte("fake\ntargz"), 0o644))
+	mirror := filepath.Join(t.TempDir(), "mirror")
+	old := filepath.Join(mirror, "foo", "0.9.0", "bin.tar.gz")
+	require.NoError(t, os.MkdirAll(filepath.Dir(old), 0o755))
+	require.NoError(t, os.WriteFile(old, []byte("old"), 0o644))
+
+	ctx := context.New(config.Project{
+		Dist: folder,
+		Blobs: []config.Blob{
+			{
+				Bucket:   mirror,
+				Provider: "file",
+				Folder:   "foo/{{ .Version }}",
+				Latest:   config.BlobLatest{Folder: "foo"},
+			},
+		},
+	})
+	ctx.Version = "1.0.0"
+	ctx.Git = context.GitInfo{CurrentTag: "v1.0.0"}
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Type: artifact.UploadableArchive,
+		Name: "bin.tar.gz",
+		Path: tgzpath,
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Publish(ctx), "latest folder foo can't contain the release folder foo/1.0.0")
+
+	require.FileExists(t, old)
+	require.NoFileExists(t, filepath.Join(mirror, "foo", "1.0.0", "bin.tar.gz"))
 }
 
 func setEnv(env map[string]string) {
@@ -317,3 +461,167 @@ func unsetEnv(env map[string]string) {
 		os.Unsetenv(k)
 	}
 }
+
+func TestOpenData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bin.tar.gz")
+	require.NoError(t, os.WriteFile(path, []byte("fake\ntargz"), 0o644))
+	ctx := context.New(config.Project{})
+
+	t.Run("plain", func(t *testing.T) {
+		data, size, err := openData(ctx, config.Blob{}, path)
+		require.NoError(t, err)
+		defer data.Close()
+		require.Equal(t, int64(10), size)
+		bts, err := io.ReadAll(data)
+		require.NoError(t, err)
+		require.Equal(t, "fake\ntargz", string(bts))
+	})
+
+	t.Run("kms", func(t *testing.T) {
+		key, err := localsecrets.NewRandomKey()
+		require.NoError(t, err)
+		url := "base64key://" + base64.URLEncoding.EncodeToString(key[:])
+		data, size, err := openData(ctx, config.Blob{KMSKey: url}, path)
+		require.NoError(t, err)
+		defer data.Close()
+		bts, err := io.ReadAll(data)
+		require.NoError(t, err)
+		require.Equal(t, int64(len(bts)), size)
+
+		keeper := localsecrets.NewKeeper(key)
+		defer keeper.Close()
+		plain, err := keeper.Decrypt(ctx, bts)
+		require.NoError(t, err)
+		require.Equal(t, "fake\ntargz", string(plain))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, _, err := openData(ctx, config.Blob{}, "nope.txt")
+		require.EqualError(t, err, "failed to open file nope.txt: open nope.txt: no such file or directory")
+	})
+
+	t.Run("invalid kms", func(t *testing.T) {
+		_, _, err := openData(ctx, config.Blob{KMSKey: "nope://foo"}, path)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to open kms nope://foo")
+	})
+}
+
+func TestProductionUploader(t *testing.T) {
+	folder := t.TempDir()
+	ctx := context.New(config.Project{})
+	up := &productionUploader{}
+	require.NoError(t, up.Open(ctx, "file://"+filepath.ToSlash(folder)))
+	content := strings.Repeat("a", 1024)
+	require.NoError(t, up.Upload(ctx, "foo/bar.txt", strings.NewReader(content), int64(len(content)), uploadOptions{
+		ContentType:        "text/plain; charset=utf-8",
+		CacheControl:       "max-age=60",
+		ContentDisposition: "attachment; filename=bar.txt",
+	}))
+
+	attrs, err := up.bucket.Attributes(ctx, "foo/bar.txt")
+	require.NoError(t, err)
+	require.Equal(t, "text/plain; charset=utf-8", attrs.ContentType)
+	require.Equal(t, "max-age=60", attrs.CacheControl)
+	require.Equal(t, "attachment; filename=bar.txt", attrs.ContentDisposition)
+	require.NoError(t, up.Close())
+
+	bts, err := os.ReadFile(filepath.Join(folder, "foo", "bar.txt"))
+	require.NoError(t, err)
+	require.Equal(t, content, string(bts))
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+func TestProductionUploaderFailingReader(t *testing.T) {
+	folder := t.TempDir()
+	ctx := context.New(config.Project{})
+	up := &productionUploader{}
+	require.NoError(t, up.Open(ctx, "file://"+filepath.ToSlash(folder)))
+	defer up.Close()
+
+	data := io.MultiReader(strings.NewReader("partial"), failingReader{})
+	require.EqualError(t, up.Upload(ctx, "foo/bar.txt", data, 1024, uploadOptions{}), "read failed")
+
+	exists, err := up.bucket.Exists(ctx, "foo/bar.txt")
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.NoFileExists(t, filepath.Join(folder, "foo", "bar.txt"))
+}
+
+func TestProductionUploaderACLNotSupported(t *testing.T) {
+	ctx := context.New(config.Project{})
+	up := &productionUploader{}
+	require.NoError(t, up.Open(ctx, "file://"+filepath.ToSlash(t.TempDir())))
+	defer up.Close()
+	require.EqualError(t, up.Upload(ctx, "bar.txt", strings.NewReader("a"), 1, uploadOptions{
+		ACL: "public-read",
+	}), "blob (key \"bar.txt\") (code=Unknown): acl is not supported by this provider")
+}
+
+func TestOptionsFor(t *testing.T) {
+	ctx := context.New(config.Project{ProjectName: "foo"})
+	ctx.Git.CurrentTag = "v1.2.3"
+
+	t.Run("defaults", func(t *testing.T) {
+		for name, contentType := range map[string]string{
+			"checksums.txt":   "text/plain; charset=utf-8",
+			"foo.tar.gz":      "application/gzip",
+			"foo.tar.gz.sig":  "application/pgp-signature",
+			"foo_1.2.3.DEB":   "application/vnd.debian.binary-package",
+			"foo-1.2.3.rpm":   "application/x-rpm",
+			"foo_windows.zip": "application/zip",
+			"foo":             "",
+		} {
+			opts, err := optionsFor(ctx, config.Blob{
+				ContentDisposition: "attachment; filename={{ .Filename }}",
+			}, name, nil)
+			require.NoError(t, err)
+			require.Equal(t, uploadOptions{
+				ContentType:        contentType,
+				ContentDisposition: "attachment; filename=" + name,
+			}, opts)
+		}
+	})
+
+	t.Run("templates", func(t *testing.T) {
+		opts, err := optionsFor(ctx, config.Blob{
+			ContentType:        "{{ if eq .Os \"windows\" }}application/x-msdownload{{ end }}",
+			CacheControl:       "max-age={{ if .IsSnapshot }}0{{ else }}3600{{ end }}",
+			ContentDisposition: "inline",
+			ACL:                "{{ .ProjectName }}-acl",
+		}, "foo.exe", &artifact.Artifact{
+			Name: "foo.exe",
+			Goos: "windows",
+		})
+		require.NoError(t, err)
+		require.Equal(t, uploadOptions{
+			ContentType:        "application/x-msdownload",
+			CacheControl:       "max-age=3600",
+			ContentDisposition: "inline",
+			ACL:                "foo-acl",
+		}, opts)
+	})
+
+	t.Run("artifact fields without artifact", func(t *testing.T) {
+		opts, err := optionsFor(ctx, config.Blob{
+			ContentType: "{{ if eq .Os \"windows\" }}application/x-msdownload{{ end }}",
+		}, "foo.txt", nil)
+		require.NoError(t, err)
+		require.Equal(t, uploadOptions{
+			ContentType: "text/plain; charset=utf-8",
+		}, opts)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := optionsFor(ctx, config.Blob{
+			CacheControl: "{{ .Nope }",
+		}, "foo.txt", nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to template cache_control for foo.txt")
+	})
+}
@@ -0,0 +1,256 @@
+package blob
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/goreleaser/goreleaser/internal/semerrgroup"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const defaultIndexHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .ProjectName }} {{ .Tag }}</title>
+</head>
+<body>
+<h1>{{ .ProjectName }} {{ .Tag }}</h1>
+<table>
+<tr><th>File</th><th>Size</th><th>SHA256</th></tr>
+{{- range .Files }}
+<tr><td><a href="{{ .Name }}">{{ .Name }}</a></td><td>{{ .Size }}</td><td><code>{{ .Checksum }}</code></td></tr>
+{{- end }}
+</table>
+</body>
+</html>
+`
+
+// indexFile is a file listed in the index pages.
+type indexFile struct {
+	Name     string `json:"name"`
+	Size     int64  `json:"size"`
+	Checksum string `json:"sha256"`
+}
+
+// index is the default content of the index.json page.
+type index struct {
+	ProjectName string      `json:"project_name"`
+	Tag         string      `json:"tag"`
+	Version     string      `json:"version"`
+	Files       []indexFile `json:"files"`
+}
+
+// page is a generated file uploaded along with the release files.
+type page struct {
+	name    string
+	content []byte
+}
+
+// indexPages renders the index.html and index.json pages listing the given
+// files, using the configured templates if any.
+func indexPages(ctx *context.Context, conf config.Blob, items []uploadItem) ([]page, error) {
+	files := make([]indexFile, 0, len(items))
+	for _, item := range items {
+		file, err := indexFileFor(item)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name < files[j].Name
+	})
+
+	html, err := renderPage(ctx, conf.Index.HTMLTemplate, defaultIndexHTML, files, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render index.html: %w", err)
+	}
+
+	var jsn []byte
+	if conf.Index.JSONTemplate == "" {
+		jsn, err = json.MarshalIndent(index{
+			ProjectName: ctx.Config.ProjectName,
+			Tag:         ctx.Git.CurrentTag,
+			Version:     ctx.Version,
+			Files:       files,
+		}, "", "  ")
+		jsn = append(jsn, '\n')
+	} else {
+		jsn, err = renderPage(ctx, conf.Index.JSONTemplate, "", files, false)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render index.json: %w", err)
+	}
+
+	return []page{
+		{name: "index.html", content: html},
+		{name: "index.json", content: jsn},
+	}, nil
+}
+
+// indexFileFor returns the size and checksum of the local file of the given
+// item, which are not the ones of the uploaded object if it is encrypted.
+func indexFileFor(item uploadItem) (indexFile, error) {
+	f, err := os.Open(item.path)
+	if err != nil {
+		return indexFile{}, fmt.Errorf("failed to open file %s: %w", item.path, err)
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return indexFile{}, fmt.Errorf("failed to read file %s: %w", item.path, err)
+	}
+	return indexFile{
+		Name:     item.name,
+		Size:     size,
+		Checksum: hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// renderPage renders the template read from the given file, or the default
+// template if no file is given, with the list of files as the .Files field.
+// HTML pages are rendered with the values escaped, so file names can't break
+// their markup.
+func renderPage(ctx *context.Context, file, def string, files []indexFile, html bool) ([]byte, error) {
+	content := def
+	if file != "" {
+		bts, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		content = string(bts)
+	}
+	t := tmpl.New(ctx).WithExtraFields(tmpl.Fields{
+		"Files": files,
+	})
+	apply := t.Apply
+	if html {
+		apply = t.ApplyHTML
+	}
+	out, err := apply(content)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
+
+// uploadPages uploads the given pages to the given folder. Pages are meant to
+// be displayed by browsers, so they are never uploaded as attachments, and
+// they are not encrypted with the KMS key.
+func uploadPages(ctx *context.Context, conf config.Blob, up uploader, pages []page, folder, bucketURL string) error {
+	g := semerrgroup.New(ctx.Parallelism)
+	for _, p := range pages {
+		p := p
+		g.Go(func() error {
+			return uploadContent(ctx, conf, up, p.content, path.Join(folder, p.name), bucketURL)
+		})
+	}
+	return g.Wait()
+}
+
+func uploadContent(ctx *context.Context, conf config.Blob, up uploader, content []byte, uploadFile, bucketURL string) error {
+	opts, err := optionsFor(ctx, conf, path.Base(uploadFile), nil)
+	if err != nil {
+		return err
+	}
+	opts.ContentDisposition = "inline"
+	if err := up.Upload(ctx, uploadFile, bytes.NewReader(content), int64(len(content)), opts); err != nil {
+		return handleError(err, bucketURL)
+	}
+	return nil
+}
+
+// uploadLatest updates the latest folder and file, if configured, unless the
+// current tag is a prerelease. Files of previous releases are removed from the
+// latest folder once the current ones are uploaded.
+func uploadLatest(ctx *context.Context, conf config.Blob, up uploader, items []uploadItem, pages []page, folder, bucketURL string) error {
+	if conf.Latest.Folder == "" && conf.Latest.File == "" {
+		return nil
+	}
+	if ctx.Semver.Prerelease != "" {
+		log.WithField("tag", ctx.Git.CurrentTag).Info("prerelease, not updating latest")
+		return nil
+	}
+
+	latest, err := latestFolder(ctx, conf, folder)
+	if err != nil {
+		return err
+	}
+	if latest != "" {
+		if err := uploadItems(ctx, conf, up, items, latest, bucketURL); err != nil {
+			return err
+		}
+		if err := uploadPages(ctx, conf, up, pages, latest, bucketURL); err != nil {
+			return err
+		}
+		if err := pruneFolder(ctx, up, items, pages, latest, bucketURL); err != nil {
+			return err
+		}
+	}
+
+	file, err := tmpl.New(ctx).Apply(conf.Latest.File)
+	if err != nil {
+		return fmt.Errorf("failed to template latest file: %w", err)
+	}
+	if file != "" {
+		return uploadContent(ctx, conf, up, []byte(ctx.Git.CurrentTag+"\n"), file, bucketURL)
+	}
+	return nil
+}
+
+// latestFolder renders the latest folder, making sure the release folder is
+// not inside of it, as its stale files are deleted.
+func latestFolder(ctx *context.Context, conf config.Blob, folder string) (string, error) {
+	latest, err := tmpl.New(ctx).Apply(conf.Latest.Folder)
+	if err != nil {
+		return "", fmt.Errorf("failed to template latest folder: %w", err)
+	}
+	if latest == "" {
+		return "", nil
+	}
+	latest = path.Clean(latest)
+	if folder = path.Clean(folder); folder == latest || strings.HasPrefix(folder, latest+"/") {
+		return "", fmt.Errorf("latest folder %s can't contain the release folder %s", latest, folder)
+	}
+	return latest, nil
+}
+
+// pruneFolder deletes the files directly in the given folder which are
+// neither one of the given items nor pages. Files in its sub folders are kept.
+func pruneFolder(ctx *context.Context, up uploader, items []uploadItem, pages []page, folder, bucketURL string) error {
+	keep := map[string]bool{}
+	for _, item := range items {
+		keep[path.Join(folder, item.name)] = true
+	}
+	for _, p := range pages {
+		keep[path.Join(folder, p.name)] = true
+	}
+
+	existing, err := up.List(ctx, folder+"/")
+	if err != nil {
+		return handleError(err, bucketURL)
+	}
+	for _, file := range existing {
+		if keep[file] || strings.Contains(strings.TrimPrefix(file, folder+"/"), "/") {
+			continue
+		}
+		log.WithField("path", file).Info("deleting stale file")
+		if err := up.Delete(ctx, file); err != nil {
+			return handleError(err, bucketURL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+package blob
+
+import (
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+type upload struct {
+	content string
+	opts    uploadOptions
+}
+
+type recordingUploader struct {
+	lock    sync.Mutex
+	uploads map[string]upload
+}
+
+func (u *recordingUploader) Close() error                            { return nil }
+func (u *recordingUploader) Open(_ *context.Context, _ string) error { return nil }
+
+func (u *recordingUploader) Upload(_ *context.Context, path string, data io.Reader, _ int64, opts uploadOptions) error {
+	bts, err := io.ReadAll(data)
+	if err != nil {
+		return err
+	}
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if u.uploads == nil {
+		u.uploads = map[string]upload{}
+	}
+	u.uploads[path] = upload{content: string(bts), opts: opts}
+	return nil
+}
+
+func (u *recordingUploader) List(_ *context.Context, prefix string) ([]string, error) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	var paths []string
+	for path := range u.uploads {
+		if strings.HasPrefix(path, prefix) {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+func (u *recordingUploader) Delete(_ *context.Context, path string) error {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	delete(u.uploads, path)
+	return nil
+}
+
+func (u *recordingUploader) paths() []string {
+	var paths []string
+	for path := range u.uploads {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func indexContext(t *testing.T) (*context.Context, []uploadItem) {
+	t.Helper()
+	folder := t.TempDir()
+	var items []uploadItem
+	for name, content := range map[string]string{
+		"foo.tar.gz":    "fake\ntargz",
+		"checksums.txt": "fake checksums",
+	} {
+		path := filepath.Join(folder, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		items = append(items, uploadItem{name: name, path: path})
+	}
+	ctx := context.New(config.Project{ProjectName: "foo"})
+	ctx.Git.CurrentTag = "v1.2.3"
+	ctx.Version = "1.2.3"
+	return ctx, items
+}
+
+func TestIndexPages(t *testing.T) {
+	ctx, items := indexContext(t)
+	pages, err := indexPages(ctx, config.Blob{}, items)
+	require.NoError(t, err)
+	require.Len(t, pages, 2)
+
+	require.Equal(t, "index.html", pages[0].name)
+	html := string(pages[0].content)
+	require.Contains(t, html, "<title>foo v1.2.3</title>")
+	require.Contains(t, html, `<tr><td><a href="checksums.txt">checksums.txt</a></td><td>14</td>`)
+	require.Contains(t, html, `<tr><td><a href="foo.tar.gz">foo.tar.gz</a></td><td>10</td>`)
+
+	require.Equal(t, "index.json", pages[1].name)
+	require.JSONEq(t, `{
+		"project_name": "foo",
+		"tag": "v1.2.3",
+		"version": "1.2.3",
+		"files": [
+			{"name": "checksums.txt", "size": 14, "sha256": "72d7aa8f7372ed10b23c946887fdecca5d5430ff6dcfb9f18a321886f21dd9a3"},
+			{"name": "foo.tar.gz", "size": 10, "sha256": "2b769735d7620a72b3e420ee862b2ace9af35ed21f05178b29b00b5e3b481768"}
+		]
+	}`, string(pages[1].content))
+}
+
+func TestIndexPagesEscaping(t *testing.T) {
+	ctx, items := indexContext(t)
+	path := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(path, []byte("foo"), 0o644))
+	items = append(items, uploadItem{name: `<b>"bar"</b>.txt`, path: path})
+
+	pages, err := indexPages(ctx, config.Blob{}, items)
+	require.NoError(t, err)
+	html := string(pages[0].content)
+	require.NotContains(t, html, "<b>")
+	require.Contains(t, html, `<a href="%3cb%3e%22bar%22%3c/b%3e.txt">&lt;b&gt;&#34;bar&#34;&lt;/b&gt;.txt</a>`)
+	require.Contains(t, string(pages[1].content), `"name": "\u003cb\u003e\"bar\"\u003c/b\u003e.txt"`)
+}
+
+func TestIndexPagesTemplates(t *testing.T) {
+	ctx, items := indexContext(t)
+	folder := t.TempDir()
+	htmlTmpl := filepath.Join(folder, "index.html.tmpl")
+	require.NoError(t, os.WriteFile(htmlTmpl, []byte("{{ .Tag }}:{{ range .Files }} {{ .Name }}{{ end }}"), 0o644))
+	jsonTmpl := filepath.Join(folder, "index.json.tmpl")
+	require.NoError(t, os.WriteFile(jsonTmpl, []byte(`[{{ range $i, $f := .Files }}{{ if $i }},{{ end }}"{{ $f.Name }}"{{ end }}]`), 0o644))
+
+	pages, err := indexPages(ctx, config.Blob{
+		Index: config.BlobIndex{
+			Enabled:      true,
+			HTMLTemplate: htmlTmpl,
+			JSONTemplate: jsonTmpl,
+		},
+	}, items)
+	require.NoError(t, err)
+	require.Equal(t, "v1.2.3: checksums.txt foo.tar.gz", string(pages[0].content))
+	require.Equal(t, `["checksums.txt","foo.tar.gz"]`, string(pages[1].content))
+}
+
+func TestIndexPagesErrors(t *testing.T) {
+	ctx, items := indexContext(t)
+
+	t.Run("missing template", func(t *testing.T) {
+		_, err := indexPages(ctx, config.Blob{
+			Index: config.BlobIndex{HTMLTemplate: "nope.tmpl"},
+		}, items)
+		require.EqualError(t, err, "failed to render index.html: open nope.tmpl: no such file or directory")
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "index.json.tmpl")
+		require.NoError(t, os.WriteFile(file, []byte("{{ .Nope }"), 0o644))
+		_, err := indexPages(ctx, config.Blob{
+			Index: config.BlobIndex{JSONTemplate: file},
+		}, items)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to render index.json")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := indexPages(ctx, config.Blob{}, []uploadItem{{name: "nope", path: "nope"}})
+		require.EqualError(t, err, "failed to open file nope: open nope: no such file or directory")
+	})
+}
+
+func TestUploadPages(t *testing.T) {
+	ctx := context.New(config.Project{})
+	up := &recordingUploader{}
+	require.NoError(t, uploadPages(ctx, config.Blob{
+		ContentDisposition: "attachment; filename={{ .Filename }}",
+		CacheControl:       "max-age=60",
+	}, up, []page{
+		{name: "index.html", content: []byte("<html></html>")},
+		{name: "index.json", content: []byte("{}")},
+	}, "foo/v1.2.3", ""))
+	require.Equal(t, map[string]upload{
+		"foo/v1.2.3/index.html": {
+			content: "<html></html>",
+			opts: uploadOptions{
+				ContentType:        "text/html; charset=utf-8",
+				CacheControl:       "max-age=60",
+				ContentDisposition: "inline",
+			},
+		},
+		"foo/v1.2.3/index.json": {
+			content: "{}",
+			opts: uploadOptions{
+				ContentType:        "application/json",
+				CacheControl:       "max-age=60",
+				ContentDisposition: "inline",
+			},
+		},
+	}, up.uploads)
+}
+
+func TestUploadLatest(t *testing.T) {
+	conf := config.Blob{
+		Latest: config.BlobLatest{
+			Folder: "{{ .ProjectName }}/latest",
+			File:   "{{ .ProjectName }}/latest.txt",
+		},
+	}
+	pages := []page{{name: "index.html", content: []byte("<html></html>")}}
+
+	t.Run("release", func(t *testing.T) {
+		ctx, items := indexContext(t)
+		up := &recordingUploader{}
+		require.NoError(t, uploadLatest(ctx, conf, up, items, pages, "foo/v1.2.3", ""))
+		require.Equal(t, []string{
+			"foo/latest.txt",
+			"foo/latest/checksums.txt",
+			"foo/latest/foo.tar.gz",
+			"foo/latest/index.html",
+		}, up.paths())
+		require.Equal(t, "v1.2.3\n", up.uploads["foo/latest.txt"].content)
+		require.Equal(t, "fake\ntargz", up.uploads["foo/latest/foo.tar.gz"].content)
+	})
+
+	t.Run("stale files", func(t *testing.T) {
+		ctx, items := indexContext(t)
+		up := &recordingUploader{uploads: map[string]upload{
+			"foo/latest/foo_1.0.0.tar.gz": {content: "old"},
+			"foo/latest/old/Release":      {content: "old"},
+			"foo/latest/foo.tar.gz":       {content: "old"},
+			"foo/latest.txt":              {content: "v1.0.0\n"},
+			"foo/v1.0.0/foo_1.0.0.tar.gz": {content: "old"},
+			"foo/latest2/foo.tar.gz":      {content: "other"},
+		}}
+		require.NoError(t, uploadLatest(ctx, conf, up, items, pages, "foo/v1.2.3", ""))
+		require.Equal(t, []string{
+			"foo/latest.txt",
+			"foo/latest/checksums.txt",
+			"foo/latest/foo.tar.gz",
+			"foo/latest/index.html",
+			"foo/latest/old/Release",
+			"foo/latest2/foo.tar.gz",
+			"foo/v1.0.0/foo_1.0.0.tar.gz",
+		}, up.paths())
+		require.Equal(t, "fake\ntargz", up.uploads["foo/latest/foo.tar.gz"].content)
+		require.Equal(t, "v1.2.3\n", up.uploads["foo/latest.txt"].content)
+	})
+
+	t.Run("release folder inside latest folder", func(t *testing.T) {
+		for _, folder := range []string{"foo", "foo/v1.2.3", "./foo/"} {
+			ctx, items := indexContext(t)
+			up := &recordingUploader{uploads: map[string]upload{
+				"foo/v1.0.0/foo.tar.gz": {content: "old"},
+			}}
+			require.EqualError(t, uploadLatest(ctx, config.Blob{
+				Latest: config.BlobLatest{Folder: folder},
+			}, up, items, pages, "foo/v1.2.3", ""), "latest folder "+path.Clean(folder)+" can't contain the release folder foo/v1.2.3")
+			require.Equal(t, []string{"foo/v1.0.0/foo.tar.gz"}, up.paths())
+		}
+	})
+
+	t.Run("prerelease", func(t *testing.T) {
+		ctx, items := indexContext(t)
+		ctx.Semver.Prerelease = "rc1"
+		up := &recordingUploader{}
+		require.NoError(t, uploadLatest(ctx, conf, up, items, pages, "foo/v1.2.3", ""))
+		require.Empty(t, up.uploads)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		ctx, items := indexContext(t)
+		up := &recordingUploader{}
+		require.NoError(t, uploadLatest(ctx, config.Blob{}, up, items, pages, "foo/v1.2.3", ""))
+		require.Empty(t, up.uploads)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		ctx, items := indexContext(t)
+		up := &recordingUploader{}
+		require.Error(t, uploadLatest(ctx, config.Blob{
+			Latest: config.BlobLatest{File: "{{ .Nope }}"},
+		}, up, items, pages, "foo/v1.2.3", ""))
+	})
+}
@@ -0,0 +1,106 @@
+package blob
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"gocloud.dev/blob"
+)
+
+// nolint: gochecknoglobals
+var contentTypes = map[string]string{
+	".asc":  "application/pgp-signature",
+	".deb":  "application/vnd.debian.binary-package",
+	".gz":   "application/gzip",
+	".html": "text/html; charset=utf-8",
+	".json": "application/json",
+	".md":   "text/markdown; charset=utf-8",
+	".rpm":  "application/x-rpm",
+	".sbom": "application/json",
+	".sig":  "application/pgp-signature",
+	".tgz":  "application/gzip",
+	".txt":  "text/plain; charset=utf-8",
+	".xml":  "application/xml",
+	".xz":   "application/x-xz",
+	".yaml": "application/x-yaml",
+	".yml":  "application/x-yaml",
+	".zip":  "application/zip",
+}
+
+// uploadOptions are the metadata set on an uploaded file.
+type uploadOptions struct {
+	ContentType        string
+	CacheControl       string
+	ContentDisposition string
+	ACL                string
+}
+
+// optionsFor renders the metadata templates of the given blob config for the
+// file with the given name, which artifact may be nil for extra files and
+// index pages, in which case the artifact fields are empty.
+func optionsFor(ctx *context.Context, conf config.Blob, name string, a *artifact.Artifact) (uploadOptions, error) {
+	if a == nil {
+		a = &artifact.Artifact{}
+	}
+	t := tmpl.New(ctx).WithArtifact(a, nil).WithExtraFields(tmpl.Fields{
+		"Filename": name,
+	})
+
+	var opts uploadOptions
+	for _, field := range []struct {
+		name  string
+		tmpl  string
+		value *string
+	}{
+		{"content_type", conf.ContentType, &opts.ContentType},
+		{"cache_control", conf.CacheControl, &opts.CacheControl},
+		{"content_disposition", conf.ContentDisposition, &opts.ContentDisposition},
+		{"acl", conf.ACL, &opts.ACL},
+	} {
+		value, err := t.Apply(field.tmpl)
+		if err != nil {
+			return opts, fmt.Errorf("failed to template %s for %s: %w", field.name, name, err)
+		}
+		*field.value = strings.TrimSpace(value)
+	}
+	if opts.ContentType == "" {
+		opts.ContentType = contentTypes[strings.ToLower(path.Ext(name))]
+	}
+	return opts, nil
+}
+
+// writerOptions converts the upload options to the blob writer options.
+// An empty content type lets the provider detect it from the file contents.
+func (opts uploadOptions) writerOptions() *blob.WriterOptions {
+	wopts := &blob.WriterOptions{
+		ContentType:        opts.ContentType,
+		CacheControl:       opts.CacheControl,
+		ContentDisposition: opts.ContentDisposition,
+	}
+	if opts.ACL == "" {
+		return wopts
+	}
+	acl := opts.ACL
+	wopts.BeforeWrite = func(as func(interface{}) bool) error {
+		var input *s3manager.UploadInput
+		if as(&input) {
+			input.ACL = aws.String(acl)
+			return nil
+		}
+		var w *storage.Writer
+		if as(&w) {
+			w.PredefinedACL = acl
+			return nil
+		}
+		return fmt.Errorf("acl is not supported by this provider")
+	}
+	return wopts
+}
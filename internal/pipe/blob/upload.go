@@ -1,11 +1,15 @@
 package blob
 
 import (
+	"bytes"
+	stdctx "context"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/apex/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -19,6 +23,7 @@ import (
 
 	// Import the blob packages we want to be able to open.
 	_ "gocloud.dev/blob/azureblob"
+	_ "gocloud.dev/blob/fileblob"
 	_ "gocloud.dev/blob/gcsblob"
 	_ "gocloud.dev/blob/s3blob"
 
@@ -34,6 +39,10 @@ func urlFor(ctx *context.Context, conf config.Blob) (string, error) {
 		return "", err
 	}
 
+	if conf.Provider == "file" {
+		return fileURLFor(bucket)
+	}
+
 	bucketURL := fmt.Sprintf("%s://%s", conf.Provider, bucket)
 
 	if conf.Provider != "s3" {
@@ -59,6 +68,29 @@ func urlFor(ctx *context.Context, conf config.Blob) (string, error) {
 	return bucketURL, nil
 }
 
+// fileURLFor returns the URL of a bucket in the given local directory, which
+// is created if needed. Metadata is not written along the files, so the
+// directory only contains the uploaded files.
+func fileURLFor(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.ToSlash(dir)
+	if !strings.HasPrefix(dir, "/") {
+		// windows paths, e.g. file:///C:/foo
+		dir = "/" + dir
+	}
+	query := url.Values{}
+	query.Add("create_dir", "true")
+	query.Add("metadata", "skip")
+	return (&url.URL{
+		Scheme:   "file",
+		Path:     dir,
+		RawQuery: query.Encode(),
+	}).String(), nil
+}
+
 // Takes goreleaser context(which includes artificats) and bucketURL for
 // upload to destination (eg: gs://gorelease-bucket) using the given uploader
 // implementation.
@@ -68,6 +100,11 @@ func doUpload(ctx *context.Context, conf config.Blob) error {
 		return err
 	}
 
+	// fail before uploading anything if the latest folder is invalid
+	if _, err := latestFolder(ctx, conf, folder); err != nil {
+		return err
+	}
+
 	bucketURL, err := urlFor(ctx, conf)
 	if err != nil {
 		return err
@@ -84,6 +121,13 @@ func doUpload(ctx *context.Context, conf config.Blob) error {
 	if len(conf.IDs) > 0 {
 		filter = artifact.And(filter, artifact.ByIDs(conf.IDs...))
 	}
+	if len(conf.Repositories) > 0 {
+		// repository files are only uploaded when explicitly asked for
+		filter = artifact.Or(
+			filter,
+			artifact.And(artifact.ByType(artifact.RepositoryFile), artifact.ByIDs(conf.Repositories...)),
+		)
+	}
 
 	up := newUploader(ctx)
 	if err := up.Open(ctx, bucketURL); err != nil {
@@ -91,17 +135,12 @@ func doUpload(ctx *context.Context, conf config.Blob) error {
 	}
 	defer up.Close()
 
-	g := semerrgroup.New(ctx.Parallelism)
+	var items []uploadItem
 	for _, artifact := range ctx.Artifacts.Filter(filter).List() {
-		artifact := artifact
-		g.Go(func() error {
-			// TODO: replace this with ?prefix=folder on the bucket url
-			dataFile := artifact.Path
-			uploadFile := path.Join(folder, artifact.Name)
-
-			err := uploadData(ctx, conf, up, dataFile, uploadFile, bucketURL)
-
-			return err
+		items = append(items, uploadItem{
+			name:     artifact.Name,
+			path:     artifact.Path,
+			artifact: artifact,
 		})
 	}
 
@@ -110,27 +149,64 @@ func doUpload(ctx *context.Context, conf config.Blob) error {
 		return err
 	}
 	for name, fullpath := range files {
-		name := name
-		fullpath := fullpath
-		g.Go(func() error {
-			uploadFile := path.Join(folder, name)
+		items = append(items, uploadItem{
+			name: name,
+			path: fullpath,
+		})
+	}
 
-			err := uploadData(ctx, conf, up, fullpath, uploadFile, bucketURL)
+	if err := uploadItems(ctx, conf, up, items, folder, bucketURL); err != nil {
+		return err
+	}
 
+	var pages []page
+	if conf.Index.Enabled {
+		pages, err = indexPages(ctx, conf, items)
+		if err != nil {
 			return err
-		})
+		}
+		if err := uploadPages(ctx, conf, up, pages, folder, bucketURL); err != nil {
+			return err
+		}
 	}
 
+	return uploadLatest(ctx, conf, up, items, pages, folder, bucketURL)
+}
+
+// uploadItem is a file to be uploaded, which artifact is nil for extra
+// files.
+type uploadItem struct {
+	name     string
+	path     string
+	artifact *artifact.Artifact
+}
+
+func uploadItems(ctx *context.Context, conf config.Blob, up uploader, items []uploadItem, folder, bucketURL string) error {
+	g := semerrgroup.New(ctx.Parallelism)
+	for _, item := range items {
+		item := item
+		g.Go(func() error {
+			// TODO: replace this with ?prefix=folder on the bucket url
+			uploadFile := path.Join(folder, item.name)
+			return uploadData(ctx, conf, up, item.artifact, item.path, uploadFile, bucketURL)
+		})
+	}
 	return g.Wait()
 }
 
-func uploadData(ctx *context.Context, conf config.Blob, up uploader, dataFile, uploadFile, bucketURL string) error {
-	data, err := getData(ctx, conf, dataFile)
+func uploadData(ctx *context.Context, conf config.Blob, up uploader, a *artifact.Artifact, dataFile, uploadFile, bucketURL string) error {
+	opts, err := optionsFor(ctx, conf, path.Base(uploadFile), a)
+	if err != nil {
+		return err
+	}
+
+	data, size, err := openData(ctx, conf, dataFile)
 	if err != nil {
 		return err
 	}
+	defer data.Close()
 
-	err = up.Upload(ctx, uploadFile, data)
+	err = up.Upload(ctx, uploadFile, data, size, opts)
 	if err != nil {
 		return handleError(err, bucketURL)
 	}
@@ -165,31 +241,46 @@ func newUploader(ctx *context.Context) uploader {
 	return &productionUploader{}
 }
 
-func getData(ctx *context.Context, conf config.Blob, path string) ([]byte, error) {
+// openData opens the given file for upload, returning its reader and size.
+// Files are streamed from disk, unless they need to be encrypted with KMS, in
+// which case the encrypted content is kept in memory.
+func openData(ctx *context.Context, conf config.Blob, path string) (io.ReadCloser, int64, error) {
+	if conf.KMSKey == "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to open file %s: %w", path, err)
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, 0, fmt.Errorf("failed to open file %s: %w", path, err)
+		}
+		return f, info.Size(), nil
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return data, fmt.Errorf("failed to open file %s: %w", path, err)
-	}
-	if conf.KMSKey == "" {
-		return data, nil
+		return nil, 0, fmt.Errorf("failed to open file %s: %w", path, err)
 	}
 	keeper, err := secrets.OpenKeeper(ctx, conf.KMSKey)
 	if err != nil {
-		return data, fmt.Errorf("failed to open kms %s: %w", conf.KMSKey, err)
+		return nil, 0, fmt.Errorf("failed to open kms %s: %w", conf.KMSKey, err)
 	}
 	defer keeper.Close()
 	data, err = keeper.Encrypt(ctx, data)
 	if err != nil {
-		return data, fmt.Errorf("failed to encrypt with kms: %w", err)
+		return nil, 0, fmt.Errorf("failed to encrypt with kms: %w", err)
 	}
-	return data, err
+	return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
 }
 
 // uploader implements upload.
 type uploader interface {
 	io.Closer
 	Open(ctx *context.Context, url string) error
-	Upload(ctx *context.Context, path string, data []byte) error
+	Upload(ctx *context.Context, path string, data io.Reader, size int64, opts uploadOptions) error
+	List(ctx *context.Context, prefix string) ([]string, error)
+	Delete(ctx *context.Context, path string) error
 }
 
 // skipUploader is used when --skip-upload is set and will just log
@@ -199,11 +290,18 @@ type skipUploader struct{}
 func (u *skipUploader) Close() error                            { return nil }
 func (u *skipUploader) Open(_ *context.Context, _ string) error { return nil }
 
-func (u *skipUploader) Upload(_ *context.Context, path string, _ []byte) error {
+func (u *skipUploader) Upload(_ *context.Context, path string, _ io.Reader, _ int64, _ uploadOptions) error {
 	log.WithField("path", path).Warn("upload skipped because skip-publish is set")
 	return nil
 }
 
+func (u *skipUploader) List(_ *context.Context, _ string) ([]string, error) { return nil, nil }
+
+func (u *skipUploader) Delete(_ *context.Context, path string) error {
+	log.WithField("path", path).Warn("delete skipped because skip-publish is set")
+	return nil
+}
+
 // productionUploader actually do upload to.
 type productionUploader struct {
 	bucket *blob.Bucket
@@ -229,21 +327,55 @@ func (u *productionUploader) Open(ctx *context.Context, bucket string) error {
 	return nil
 }
 
-func (u *productionUploader) Upload(ctx *context.Context, filepath string, data []byte) (err error) {
-	log.WithField("path", filepath).Info("uploading")
+// maximum number of parts and default part size of multipart uploads, as
+// used by S3.
+const (
+	maxParts        = 10000
+	defaultPartSize = 5 * 1024 * 1024
+)
+
+func (u *productionUploader) Upload(ctx *context.Context, filepath string, data io.Reader, size int64, uopts uploadOptions) error {
+	log.WithField("path", filepath).WithField("size", size).Info("uploading")
 
-	opts := &blob.WriterOptions{
-		ContentDisposition: "attachment; filename=" + path.Base(filepath),
+	opts := uopts.writerOptions()
+	// the default part size is too small for very large files, so make sure
+	// they fit in the maximum number of parts.
+	if partSize := size/maxParts + 1; partSize > defaultPartSize {
+		opts.BufferSize = int(partSize)
 	}
-	w, err := u.bucket.NewWriter(ctx, filepath, opts)
+	// closing the writer commits the object, so its context is canceled
+	// first if the copy fails, which aborts the write instead.
+	wctx, cancel := stdctx.WithCancel(ctx)
+	defer cancel()
+	w, err := u.bucket.NewWriter(wctx, filepath, opts)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if cerr := w.Close(); err == nil {
-			err = cerr
+	if _, err := io.Copy(w, data); err != nil {
+		cancel()
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (u *productionUploader) List(ctx *context.Context, prefix string) ([]string, error) {
+	var paths []string
+	iter := u.bucket.List(&blob.ListOptions{Prefix: prefix})
+	for {
+		obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			return paths, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !obj.IsDir {
+			paths = append(paths, obj.Key)
 		}
-	}()
-	_, err = w.Write(data)
-	return
+	}
+}
+
+func (u *productionUploader) Delete(ctx *context.Context, path string) error {
+	return u.bucket.Delete(ctx, path)
 }
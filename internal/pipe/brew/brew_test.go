@@ -873,6 +873,18 @@ func (dc *DummyClient) CloseMilestone(ctx *context.Context, repo client.Repo, ti
 	return nil
 }
 
+func (dc *DummyClient) CreateMilestone(ctx *context.Context, repo client.Repo, title string) error {
+	return nil
+}
+
+func (dc *DummyClient) MoveOpenIssues(ctx *context.Context, repo client.Repo, from, to string) error {
+	return nil
+}
+
+func (dc *DummyClient) PullRequests(ctx *context.Context, repo client.Repo, commits []string) ([]client.PullRequest, error) {
+	return nil, nil
+}
+
 func (dc *DummyClient) CreateRelease(ctx *context.Context, body string) (releaseID string, err error) {
 	return
 }
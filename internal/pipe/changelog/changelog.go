@@ -11,9 +11,11 @@ import (
 	"strings"
 
 	"github.com/apex/log"
+	"github.com/goreleaser/goreleaser/internal/client"
 	"github.com/goreleaser/goreleaser/internal/git"
 	"github.com/goreleaser/goreleaser/internal/pipe"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
@@ -58,12 +60,28 @@ func (Pipe) Run(ctx *context.Context) error {
 	if err := checkSortDirection(ctx.Config.Changelog.Sort); err != nil {
 		return err
 	}
-
-	entries, err := buildChangelog(ctx)
-	if err != nil {
+	if err := checkUse(ctx); err != nil {
 		return err
 	}
 
+	var entries []string
+	var labels map[string][]string
+	if usesSCM(ctx) {
+		cli, err := client.New(ctx)
+		if err != nil {
+			return err
+		}
+		entries, labels, err = buildSCMChangelog(ctx, cli)
+		if err != nil {
+			return err
+		}
+	} else {
+		entries, err = buildChangelog(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
 	changelogStringJoiner := "\n"
 	if ctx.TokenType == context.TokenTypeGitLab || ctx.TokenType == context.TokenTypeGitea {
 		// We need two or more whitespace to let markdown interpret
@@ -72,9 +90,25 @@ func (Pipe) Run(ctx *context.Context) error {
 		changelogStringJoiner = "   \n"
 	}
 
+	body := strings.Join(entries, changelogStringJoiner)
+	if len(ctx.Config.Changelog.Groups) > 0 {
+		groups, err := groupEntries(ctx.Config.Changelog.Groups, entries, labels)
+		if err != nil {
+			return err
+		}
+		entries = nil
+		var sections []string
+		for _, group := range groups {
+			entries = append(entries, group.entries...)
+			sections = append(sections, fmt.Sprintf("### %s\n%s", group.title, strings.Join(group.entries, changelogStringJoiner)))
+		}
+		body = strings.Join(sections, "\n\n")
+	}
+	ctx.ChangelogEntries = entries
+
 	changelogElements := []string{
 		"## Changelog",
-		strings.Join(entries, changelogStringJoiner),
+		body,
 	}
 	if header != "" {
 		changelogElements = append([]string{header}, changelogElements...)
@@ -93,6 +127,80 @@ func (Pipe) Run(ctx *context.Context) error {
 	return os.WriteFile(path, []byte(ctx.ReleaseNotes), 0o644) //nolint: gosec
 }
 
+type entriesGroup struct {
+	title   string
+	entries []string
+}
+
+// groupEntries puts each entry in the first group, by order, matching both its
+// labels and its regexp. Groups without labels nor regexp take all the entries
+// not grouped yet, and entries which don't belong to any group are left out.
+func groupEntries(groups []config.ChangelogGroup, entries []string, labels map[string][]string) ([]entriesGroup, error) {
+	groups = append([]config.ChangelogGroup{}, groups...)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Order < groups[j].Order
+	})
+
+	grouped := map[string]bool{}
+	var result []entriesGroup
+	for _, group := range groups {
+		var re *regexp.Regexp
+		if group.Regexp != "" {
+			var err error
+			re, err = regexp.Compile(group.Regexp)
+			if err != nil {
+				return nil, fmt.Errorf("changelog: invalid regexp for group %s: %w", group.Title, err)
+			}
+		}
+
+		var groupEntries []string
+		for _, entry := range entries {
+			if grouped[entry] || !hasAnyLabel(labels[entry], group.Labels) {
+				continue
+			}
+			if re != nil && !re.MatchString(extractCommitInfo(entry)) {
+				continue
+			}
+			grouped[entry] = true
+			if re != nil && group.StripPrefix {
+				entry = stripPrefix(re, entry)
+			}
+			groupEntries = append(groupEntries, entry)
+		}
+		if len(groupEntries) == 0 {
+			continue
+		}
+		result = append(result, entriesGroup{title: group.Title, entries: groupEntries})
+	}
+	return result, nil
+}
+
+// stripPrefix removes the text matched by the regexp from the start of the
+// entry's message.
+func stripPrefix(re *regexp.Regexp, entry string) string {
+	msg := extractCommitInfo(entry)
+	loc := re.FindStringIndex(msg)
+	if loc == nil || loc[0] != 0 || loc[1] == len(msg) {
+		return entry
+	}
+	id := strings.Split(entry, " ")[0]
+	return id + " " + strings.TrimLeft(msg[loc[1]:], " ")
+}
+
+func hasAnyLabel(labels, wanted []string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	for _, label := range labels {
+		for _, w := range wanted {
+			if strings.EqualFold(label, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func loadFromFile(file string) (string, error) {
 	bts, err := os.ReadFile(file)
 	if err != nil {
@@ -128,6 +236,17 @@ func buildChangelog(ctx *context.Context) ([]string, error) {
 }
 
 func filterEntries(ctx *context.Context, entries []string) ([]string, error) {
+	if include := ctx.Config.Changelog.Filters.Include; len(include) > 0 {
+		var filters []*regexp.Regexp
+		for _, filter := range include {
+			r, err := regexp.Compile(filter)
+			if err != nil {
+				return entries, err
+			}
+			filters = append(filters, r)
+		}
+		entries = keep(filters, entries)
+	}
 	for _, filter := range ctx.Config.Changelog.Filters.Exclude {
 		r, err := regexp.Compile(filter)
 		if err != nil {
@@ -165,6 +284,18 @@ func remove(filter *regexp.Regexp, entries []string) (result []string) {
 	return result
 }
 
+func keep(filters []*regexp.Regexp, entries []string) (result []string) {
+	for _, entry := range entries {
+		for _, filter := range filters {
+			if filter.MatchString(extractCommitInfo(entry)) {
+				result = append(result, entry)
+				break
+			}
+		}
+	}
+	return result
+}
+
 func extractCommitInfo(line string) string {
 	return strings.Join(strings.Split(line, " ")[1:], " ")
 }
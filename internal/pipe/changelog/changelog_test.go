@@ -3,6 +3,7 @@ package changelog
 import (
 	"os"
 	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -448,3 +449,109 @@ func TestChangeLogWithoutReleaseFooter(t *testing.T) {
 	require.Contains(t, ctx.ReleaseNotes, "## Changelog")
 	require.Equal(t, rune(ctx.ReleaseNotes[len(ctx.ReleaseNotes)-1]), '\n')
 }
+
+func TestChangelogInclude(t *testing.T) {
+	testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "feat: added feature 1")
+	testlib.GitCommit(t, "fix: fixed bug 2")
+	testlib.GitCommit(t, "fix: typo")
+	testlib.GitCommit(t, "chore: whatever")
+	testlib.GitTag(t, "v0.0.2")
+	ctx := context.New(config.Project{
+		Changelog: config.Changelog{
+			Filters: config.Filters{
+				Include: []string{"^feat:", "^fix:"},
+				Exclude: []string{"typo"},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v0.0.2"
+	entries, err := buildChangelog(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Contains(t, entries[0], "fix: fixed bug 2")
+	require.Contains(t, entries[1], "feat: added feature 1")
+}
+
+func TestChangelogIncludeInvalidRegex(t *testing.T) {
+	testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "second")
+	testlib.GitTag(t, "v0.0.2")
+	ctx := context.New(config.Project{
+		Changelog: config.Changelog{
+			Filters: config.Filters{
+				Include: []string{"(?iasdr4qasd)not a valid regex i guess"},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.EqualError(t, Pipe{}.Run(ctx), "error parsing regexp: invalid or unsupported Perl syntax: `(?ia`")
+}
+
+func TestChangelogGroupsRegexp(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "feat: added feature 1")
+	testlib.GitCommit(t, "fix(foo): fixed bug 2")
+	testlib.GitCommit(t, "feat!: added breaking feature 3")
+	testlib.GitCommit(t, "chore: whatever")
+	testlib.GitTag(t, "v0.0.2")
+	ctx := context.New(config.Project{
+		Dist: folder,
+		Changelog: config.Changelog{
+			Sort: "asc",
+			Groups: []config.ChangelogGroup{
+				{Title: "Others", Order: 999},
+				{Title: "Bug fixes", Regexp: `^fix(\([^)]+\))?!?:`, Order: 1, StripPrefix: true},
+				{Title: "Docs", Regexp: `^docs:`, Order: 2},
+				{Title: "Features", Regexp: `^feat(\([^)]+\))?!?:`, Order: 0},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	require.Len(t, ctx.ChangelogEntries, 4)
+	require.Contains(t, ctx.ChangelogEntries[0], " feat!: added breaking feature 3")
+	require.Contains(t, ctx.ChangelogEntries[1], " feat: added feature 1")
+	require.Regexp(t, `^[0-9a-f]+ fixed bug 2$`, ctx.ChangelogEntries[2])
+	require.Contains(t, ctx.ChangelogEntries[3], " chore: whatever")
+
+	require.Regexp(t, "(?s)^## Changelog\n\n### Features\n.+\n\n### Bug fixes\n.+\n\n### Others\n.+\n$", ctx.ReleaseNotes)
+	require.NotContains(t, ctx.ReleaseNotes, "### Docs")
+	require.NotContains(t, ctx.ReleaseNotes, "fix(foo)")
+}
+
+func TestChangelogGroupsInvalidRegex(t *testing.T) {
+	testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "second")
+	testlib.GitTag(t, "v0.0.2")
+	ctx := context.New(config.Project{
+		Changelog: config.Changelog{
+			Groups: []config.ChangelogGroup{
+				{Title: "Features", Regexp: "(?iasdr4qasd)not a valid regex i guess"},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.EqualError(t, Pipe{}.Run(ctx), "changelog: invalid regexp for group Features: error parsing regexp: invalid or unsupported Perl syntax: `(?ia`")
+}
+
+func TestStripPrefix(t *testing.T) {
+	re := regexp.MustCompile(`^feat(\([^)]+\))?:`)
+	require.Equal(t, "abc1234 foo bar", stripPrefix(re, "abc1234 feat(scope): foo bar"))
+	require.Equal(t, "abc1234 feat:", stripPrefix(re, "abc1234 feat:"))
+	require.Equal(t, "abc1234 fix: feat: foo", stripPrefix(re, "abc1234 fix: feat: foo"))
+	require.Equal(t, "abc1234 foo", stripPrefix(regexp.MustCompile(`feat:`), "abc1234 feat: foo"))
+}
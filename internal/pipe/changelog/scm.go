@@ -0,0 +1,117 @@
+package changelog
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/git"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const useGit = "git"
+
+func checkUse(ctx *context.Context) error {
+	use := ctx.Config.Changelog.Use
+	switch use {
+	case "", useGit:
+		return nil
+	case string(context.TokenTypeGitHub), string(context.TokenTypeGitLab), string(context.TokenTypeGitea):
+		if use != string(ctx.TokenType) {
+			return fmt.Errorf("changelog: can't use %s when releasing to %s", use, ctx.TokenType)
+		}
+		return nil
+	}
+	return fmt.Errorf("changelog: invalid use: %s", use)
+}
+
+// usesSCM tells whether the changelog is built from the pull requests of the
+// SCM, instead of the git log.
+func usesSCM(ctx *context.Context) bool {
+	use := ctx.Config.Changelog.Use
+	return use != "" && use != useGit
+}
+
+// buildSCMChangelog builds the changelog from the pull requests merged between
+// the previous and the current tags, also returning the labels of each entry.
+func buildSCMChangelog(ctx *context.Context, cli client.Client) ([]string, map[string][]string, error) {
+	commits, err := getCommits(ctx.Git.CurrentTag)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prs, err := cli.PullRequests(ctx, scmRepo(ctx), commits)
+	if err != nil {
+		return nil, nil, fmt.Errorf("changelog: failed to list pull requests: %w", err)
+	}
+
+	entries := make([]string, 0, len(prs))
+	labels := map[string][]string{}
+	for _, pr := range prs {
+		entry := pullRequestEntry(ctx, pr)
+		entries = append(entries, entry)
+		labels[entry] = pr.Labels
+	}
+
+	entries, err = filterEntries(ctx, entries)
+	if err != nil {
+		return entries, labels, err
+	}
+	return sortEntries(ctx, entries), labels, nil
+}
+
+// pullRequestEntry formats the pull request like the git log entries, so it
+// can be filtered and sorted the same way.
+func pullRequestEntry(ctx *context.Context, pr client.PullRequest) string {
+	ref := fmt.Sprintf("#%d", pr.Number)
+	if ctx.TokenType == context.TokenTypeGitLab {
+		ref = fmt.Sprintf("!%d", pr.Number)
+	}
+
+	id := ref
+	if len(pr.SHA) >= 7 {
+		id = pr.SHA[:7]
+	}
+
+	entry := id + " " + pr.Title
+	if pr.Author != "" {
+		entry += " by @" + pr.Author
+	}
+	if pr.URL != "" {
+		entry += fmt.Sprintf(" in [%s](%s)", ref, pr.URL)
+	}
+	return entry
+}
+
+func scmRepo(ctx *context.Context) client.Repo {
+	repo := ctx.Config.Release.GitHub
+	switch ctx.TokenType {
+	case context.TokenTypeGitLab:
+		repo = ctx.Config.Release.GitLab
+	case context.TokenTypeGitea:
+		repo = ctx.Config.Release.Gitea
+	}
+	return client.Repo{
+		Owner: repo.Owner,
+		Name:  repo.Name,
+	}
+}
+
+// getCommits returns the full hashes of the commits between the previous and
+// the given tags.
+func getCommits(tag string) ([]string, error) {
+	prev, err := previous(tag)
+	if err != nil {
+		return nil, err
+	}
+	refs := []string{fmt.Sprintf("tags/%s..tags/%s", prev, tag)}
+	if isSHA1(prev) {
+		refs = []string{prev, tag}
+	}
+	args := append([]string{"log", "--pretty=format:%H", "--no-decorate", "--no-color"}, refs...)
+	out, err := git.Run(args...)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(out), nil
+}
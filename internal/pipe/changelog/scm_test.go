@@ -0,0 +1,250 @@
+package changelog
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/client"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUse(t *testing.T) {
+	for use, tokenType := range map[string]context.TokenType{
+		"":       context.TokenTypeGitHub,
+		"git":    context.TokenTypeGitLab,
+		"github": context.TokenTypeGitHub,
+		"gitlab": context.TokenTypeGitLab,
+		"gitea":  context.TokenTypeGitea,
+	} {
+		t.Run(use, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				Changelog: config.Changelog{Use: use},
+			})
+			ctx.TokenType = tokenType
+			require.NoError(t, checkUse(ctx))
+		})
+	}
+}
+
+func TestCheckUseInvalid(t *testing.T) {
+	ctx := context.New(config.Project{
+		Changelog: config.Changelog{Use: "svn"},
+	})
+	require.EqualError(t, checkUse(ctx), "changelog: invalid use: svn")
+}
+
+func TestCheckUseOtherSCM(t *testing.T) {
+	ctx := context.New(config.Project{
+		Changelog: config.Changelog{Use: "gitlab"},
+	})
+	ctx.TokenType = context.TokenTypeGitHub
+	require.EqualError(t, checkUse(ctx), "changelog: can't use gitlab when releasing to github")
+}
+
+func TestChangelogInvalidUse(t *testing.T) {
+	ctx := context.New(config.Project{
+		Changelog: config.Changelog{Use: "svn"},
+	})
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.EqualError(t, Pipe{}.Run(ctx), "changelog: invalid use: svn")
+}
+
+func TestBuildSCMChangelog(t *testing.T) {
+	testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "added feature 1")
+	testlib.GitCommit(t, "fixed bug 2")
+	testlib.GitTag(t, "v0.0.2")
+
+	cli := &fakeClient{
+		prs: []client.PullRequest{
+			{
+				Number: 2,
+				Title:  "fixed bug 2",
+				Author: "bar",
+				Labels: []string{"bug"},
+				URL:    "https://github.com/foo/bar/pull/2",
+				SHA:    "2222222222222222222222222222222222222222",
+			},
+			{
+				Number: 1,
+				Title:  "added feature 1",
+				Author: "foo",
+				Labels: []string{"enhancement"},
+				URL:    "https://github.com/foo/bar/pull/1",
+				SHA:    "1111111111111111111111111111111111111111",
+			},
+			{
+				Number: 3,
+				Title:  "docs: whatever",
+			},
+		},
+	}
+	ctx := context.New(config.Project{
+		Release: config.Release{
+			GitHub: config.Repo{Owner: "foo", Name: "bar"},
+		},
+		Changelog: config.Changelog{
+			Use: "github",
+			Filters: config.Filters{
+				Exclude: []string{"^docs:"},
+			},
+		},
+	})
+	ctx.TokenType = context.TokenTypeGitHub
+	ctx.Git.CurrentTag = "v0.0.2"
+
+	entries, labels, err := buildSCMChangelog(ctx, cli)
+	require.NoError(t, err)
+	require.Equal(t, client.Repo{Owner: "foo", Name: "bar"}, cli.repo)
+	require.Len(t, cli.commits, 2)
+	for _, commit := range cli.commits {
+		require.True(t, isSHA1(commit), commit)
+	}
+	require.Equal(t, []string{
+		"2222222 fixed bug 2 by @bar in [#2](https://github.com/foo/bar/pull/2)",
+		"1111111 added feature 1 by @foo in [#1](https://github.com/foo/bar/pull/1)",
+	}, entries)
+	require.Equal(t, []string{"bug"}, labels[entries[0]])
+	require.Equal(t, []string{"enhancement"}, labels[entries[1]])
+}
+
+func TestBuildSCMChangelogError(t *testing.T) {
+	testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+
+	ctx := context.New(config.Project{})
+	ctx.TokenType = context.TokenTypeGitea
+	ctx.Git.CurrentTag = "v0.0.1"
+	_, _, err := buildSCMChangelog(ctx, &fakeClient{err: errors.New("fake")})
+	require.EqualError(t, err, "changelog: failed to list pull requests: fake")
+}
+
+func TestPullRequestEntry(t *testing.T) {
+	pr := client.PullRequest{
+		Number: 10,
+		Title:  "fix: foo",
+		Author: "someone",
+		URL:    "https://gitlab.com/foo/bar/-/merge_requests/10",
+		SHA:    "abcdef1234567890abcdef1234567890abcdef12",
+	}
+
+	ctx := context.New(config.Project{})
+	ctx.TokenType = context.TokenTypeGitLab
+	require.Equal(t, "abcdef1 fix: foo by @someone in [!10](https://gitlab.com/foo/bar/-/merge_requests/10)", pullRequestEntry(ctx, pr))
+
+	ctx.TokenType = context.TokenTypeGitea
+	require.Equal(t, "#10 fix: foo", pullRequestEntry(ctx, client.PullRequest{Number: 10, Title: "fix: foo"}))
+}
+
+func TestGroupEntries(t *testing.T) {
+	entries := []string{
+		"a1 feature a",
+		"b2 bug b",
+		"c3 chore c",
+		"d4 feature and bug d",
+	}
+	labels := map[string][]string{
+		"a1 feature a":         {"enhancement"},
+		"b2 bug b":             {"Bug"},
+		"d4 feature and bug d": {"bug", "enhancement"},
+		"not in the changelog": {"bug"},
+		"c3 chore c":           {"chore"},
+	}
+
+	groups, err := groupEntries([]config.ChangelogGroup{
+		{Title: "Features", Labels: []string{"enhancement"}},
+		{Title: "Bug fixes", Labels: []string{"bug"}},
+		{Title: "Docs", Labels: []string{"documentation"}},
+		{Title: "Others"},
+	}, entries, labels)
+	require.NoError(t, err)
+	require.Equal(t, []entriesGroup{
+		{title: "Features", entries: []string{"a1 feature a", "d4 feature and bug d"}},
+		{title: "Bug fixes", entries: []string{"b2 bug b"}},
+		{title: "Others", entries: []string{"c3 chore c"}},
+	}, groups)
+
+	groups, err = groupEntries([]config.ChangelogGroup{
+		{Title: "Bug fixes", Labels: []string{"bug"}},
+	}, entries, labels)
+	require.NoError(t, err)
+	require.Equal(t, []entriesGroup{
+		{title: "Bug fixes", entries: []string{"b2 bug b", "d4 feature and bug d"}},
+	}, groups)
+}
+
+func TestChangelogGroups(t *testing.T) {
+	folder := testlib.Mktmp(t)
+	testlib.GitInit(t)
+	testlib.GitCommit(t, "first")
+	testlib.GitTag(t, "v0.0.1")
+	testlib.GitCommit(t, "added feature 1")
+	testlib.GitTag(t, "v0.0.2")
+
+	ctx := context.New(config.Project{
+		Dist: folder,
+		Changelog: config.Changelog{
+			Groups: []config.ChangelogGroup{
+				{Title: "Bug fixes", Labels: []string{"bug"}},
+				{Title: "Changes"},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v0.0.2"
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.Len(t, ctx.ChangelogEntries, 1)
+	require.Contains(t, ctx.ReleaseNotes, "## Changelog\n\n### Changes\n")
+	require.Contains(t, ctx.ReleaseNotes, "added feature 1")
+	require.NotContains(t, ctx.ReleaseNotes, "Bug fixes")
+}
+
+type fakeClient struct {
+	prs     []client.PullRequest
+	err     error
+	repo    client.Repo
+	commits []string
+}
+
+func (c *fakeClient) CloseMilestone(ctx *context.Context, repo client.Repo, title string) error {
+	return nil
+}
+
+func (c *fakeClient) CreateMilestone(ctx *context.Context, repo client.Repo, title string) error {
+	return nil
+}
+
+func (c *fakeClient) MoveOpenIssues(ctx *context.Context, repo client.Repo, from, to string) error {
+	return nil
+}
+
+func (c *fakeClient) PullRequests(ctx *context.Context, repo client.Repo, commits []string) ([]client.PullRequest, error) {
+	c.repo = repo
+	c.commits = commits
+	return c.prs, c.err
+}
+
+func (c *fakeClient) CreateRelease(ctx *context.Context, body string) (string, error) {
+	return "", nil
+}
+
+func (c *fakeClient) ReleaseURLTemplate(ctx *context.Context) (string, error) {
+	return "", nil
+}
+
+func (c *fakeClient) CreateFile(ctx *context.Context, commitAuthor config.CommitAuthor, repo client.Repo, content []byte, path, message string) error {
+	return nil
+}
+
+func (c *fakeClient) Upload(ctx *context.Context, releaseID string, artifact *artifact.Artifact, file *os.File) error {
+	return nil
+}
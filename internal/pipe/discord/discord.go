@@ -0,0 +1,117 @@
+package discord
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/apex/log"
+	"github.com/caarlos0/env/v6"
+	"github.com/goreleaser/goreleaser/internal/announcer"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const (
+	defaultAuthor = `GoReleaser`
+	defaultColor  = "3888754"
+	defaultIcon   = `https://goreleaser.com/static/avatar.png`
+)
+
+// nolint: gochecknoglobals
+var webhookURL = "https://discord.com/api/webhooks/%s/%s"
+
+type Pipe struct{}
+
+func (Pipe) String() string { return "discord" }
+
+type Config struct {
+	WebhookID    string `env:"DISCORD_WEBHOOK_ID,notEmpty"`
+	WebhookToken string `env:"DISCORD_WEBHOOK_TOKEN,notEmpty"`
+}
+
+func (Pipe) Default(ctx *context.Context) error {
+	if ctx.Config.Announce.Discord.MessageTemplate == "" {
+		ctx.Config.Announce.Discord.MessageTemplate = announcer.DefaultMessageTemplate
+	}
+	if ctx.Config.Announce.Discord.Author == "" {
+		ctx.Config.Announce.Discord.Author = defaultAuthor
+	}
+	if ctx.Config.Announce.Discord.Color == "" {
+		ctx.Config.Announce.Discord.Color = defaultColor
+	}
+	if ctx.Config.Announce.Discord.IconURL == "" {
+		ctx.Config.Announce.Discord.IconURL = defaultIcon
+	}
+	return nil
+}
+
+// message is the payload of a Discord webhook.
+type message struct {
+	Username  string  `json:"username,omitempty"`
+	AvatarURL string  `json:"avatar_url,omitempty"`
+	Embeds    []embed `json:"embeds"`
+}
+
+type embed struct {
+	Description string `json:"description"`
+	Color       int    `json:"color"`
+}
+
+func (Pipe) Announce(ctx *context.Context) error {
+	if ctx.SkipAnnounce {
+		return pipe.ErrSkipAnnounceEnabled
+	}
+	if !ctx.Config.Announce.Discord.Enabled {
+		return pipe.ErrSkipDisabledPipe
+	}
+
+	msg, err := newMessage(ctx)
+	if err != nil {
+		return fmt.Errorf("announce: failed to announce to discord: %w", err)
+	}
+
+	var cfg Config
+	if err := env.Parse(&cfg); err != nil {
+		return fmt.Errorf("announce: failed to announce to discord: %w", err)
+	}
+
+	log.Infof("posting: '%s'", msg.Embeds[0].Description)
+	if err := announcer.PostJSON(ctx, fmt.Sprintf(webhookURL, cfg.WebhookID, cfg.WebhookToken), msg, nil); err != nil {
+		return fmt.Errorf("announce: failed to announce to discord: %w", err)
+	}
+	return nil
+}
+
+func newMessage(ctx *context.Context) (message, error) {
+	conf := ctx.Config.Announce.Discord
+	t := tmpl.New(ctx)
+
+	description, err := t.Apply(conf.MessageTemplate)
+	if err != nil {
+		return message{}, err
+	}
+	author, err := t.Apply(conf.Author)
+	if err != nil {
+		return message{}, err
+	}
+	icon, err := t.Apply(conf.IconURL)
+	if err != nil {
+		return message{}, err
+	}
+	color, err := strconv.Atoi(conf.Color)
+	if err != nil {
+		return message{}, fmt.Errorf("invalid color %q: %w", conf.Color, err)
+	}
+
+	return message{
+		Username:  author,
+		AvatarURL: icon,
+		Embeds: []embed{
+			{
+				Description: description,
+				Color:       color,
+			},
+		},
+	}, nil
+}
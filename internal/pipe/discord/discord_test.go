@@ -0,0 +1,156 @@
+package discord
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/announcer"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringer(t *testing.T) {
+	require.Equal(t, Pipe{}.String(), "discord")
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, config.Discord{
+		MessageTemplate: announcer.DefaultMessageTemplate,
+		Author:          defaultAuthor,
+		Color:           defaultColor,
+		IconURL:         defaultIcon,
+	}, ctx.Config.Announce.Discord)
+}
+
+func TestAnnounceDisabled(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceSkipAnnounce(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Discord: config.Discord{
+				Enabled: true,
+			},
+		},
+	})
+	ctx.SkipAnnounce = true
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceInvalidTemplate(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Discord: config.Discord{
+				Enabled:         true,
+				MessageTemplate: "{{ .Foo }",
+			},
+		},
+	})
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to discord: template: tmpl:1: unexpected "}" in operand`)
+}
+
+func TestAnnounceInvalidColor(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Discord: config.Discord{
+				Enabled: true,
+				Color:   "blue",
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to discord: invalid color "blue": strconv.Atoi: parsing "blue": invalid syntax`)
+}
+
+func TestAnnounceMissingEnv(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Discord: config.Discord{
+				Enabled: true,
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to discord: env: environment variable "DISCORD_WEBHOOK_ID" should not be empty`)
+}
+
+func TestAnnounce(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+	setWebhook(t, srv.URL)
+
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Announce: config.Announce{
+			Discord: config.Discord{
+				Enabled:         true,
+				MessageTemplate: "{{ .ProjectName }} {{ .Tag }} is out!",
+				Author:          "{{ .ProjectName }} bot",
+				Color:           "16711680",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+	require.Equal(t, "/api/webhooks/id/token", path)
+	require.Equal(t, map[string]interface{}{
+		"username":   "foo bot",
+		"avatar_url": defaultIcon,
+		"embeds": []interface{}{
+			map[string]interface{}{
+				"description": "foo v1.0.0 is out!",
+				"color":       float64(16711680),
+			},
+		},
+	}, body)
+}
+
+func TestAnnounceError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = io.WriteString(w, `{"message": "Invalid Webhook Token", "code": 50027}`)
+	}))
+	defer srv.Close()
+	setWebhook(t, srv.URL)
+
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Discord: config.Discord{
+				Enabled: true,
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to discord: unexpected http status code 401: {"message": "Invalid Webhook Token", "code": 50027}`)
+}
+
+func setWebhook(t *testing.T, url string) {
+	t.Helper()
+	previous := webhookURL
+	webhookURL = url + "/api/webhooks/%s/%s"
+	require.NoError(t, os.Setenv("DISCORD_WEBHOOK_ID", "id"))
+	require.NoError(t, os.Setenv("DISCORD_WEBHOOK_TOKEN", "token"))
+	t.Cleanup(func() {
+		webhookURL = previous
+		require.NoError(t, os.Unsetenv("DISCORD_WEBHOOK_ID"))
+		require.NoError(t, os.Unsetenv("DISCORD_WEBHOOK_TOKEN"))
+	})
+}
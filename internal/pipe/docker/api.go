@@ -3,6 +3,7 @@ package docker
 import (
 	"context"
 	"os/exec"
+	"regexp"
 	"sync"
 
 	"github.com/apex/log"
@@ -29,13 +30,25 @@ func registerImager(use string, impl imager) {
 // imager is something that can build and push docker images.
 type imager interface {
 	Build(ctx context.Context, root string, images, flags []string) error
-	Push(ctx context.Context, image string, flags []string) error
+	Push(ctx context.Context, image string, flags []string) (digest string, err error)
 }
 
 // manifester is something that can create and push docker manifests.
 type manifester interface {
 	Create(ctx context.Context, manifest string, images, flags []string) error
-	Push(ctx context.Context, manifest string, flags []string) error
+	Push(ctx context.Context, manifest string, flags []string) (digest string, err error)
+}
+
+var digestRe = regexp.MustCompile(`sha256:[a-f0-9]{64}`)
+
+// digestFrom returns the last digest found in the given push output, or an
+// empty string if there is none.
+func digestFrom(out []byte) string {
+	matches := digestRe.FindAll(out, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return string(matches[len(matches)-1])
 }
 
 // nolint: unparam
@@ -29,24 +29,26 @@ func (m dockerManifester) Create(ctx context.Context, manifest string, images, f
 	return nil
 }
 
-func (m dockerManifester) Push(ctx context.Context, manifest string, flags []string) error {
+func (m dockerManifester) Push(ctx context.Context, manifest string, flags []string) (string, error) {
 	args := []string{"manifest", "push", manifest}
 	args = append(args, flags...)
-	if _, err := runCommand(ctx, ".", "docker", args...); err != nil {
-		return fmt.Errorf("failed to push %s: %w", manifest, err)
+	out, err := runCommand(ctx, ".", "docker", args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to push %s: %w", manifest, err)
 	}
-	return nil
+	return digestFrom(out), nil
 }
 
 type dockerImager struct {
 	buildx bool
 }
 
-func (i dockerImager) Push(ctx context.Context, image string, flags []string) error {
-	if _, err := runCommand(ctx, ".", "docker", "push", image); err != nil {
-		return fmt.Errorf("failed to push %s: %w", image, err)
+func (i dockerImager) Push(ctx context.Context, image string, flags []string) (string, error) {
+	out, err := runCommand(ctx, ".", "docker", "push", image)
+	if err != nil {
+		return "", fmt.Errorf("failed to push %s: %w", image, err)
 	}
-	return nil
+	return digestFrom(out), nil
 }
 
 func (i dockerImager) Build(ctx context.Context, root string, images, flags []string) error {
@@ -0,0 +1,116 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func init() {
+	registerManifester(usePodman, podmanManifester{binary: "podman"})
+	registerManifester(useBuildah, podmanManifester{binary: "buildah"})
+
+	registerImager(usePodman, podmanImager{binary: "podman"})
+	registerImager(useBuildah, podmanImager{binary: "buildah"})
+}
+
+// podmanManifester creates and pushes manifests using either podman or
+// buildah, which share the same manifest CLI.
+type podmanManifester struct {
+	binary string
+}
+
+func (m podmanManifester) Create(ctx context.Context, manifest string, images, flags []string) error {
+	_, _ = runCommand(ctx, ".", m.binary, "manifest", "rm", manifest)
+
+	if _, err := runCommand(ctx, ".", m.binary, "manifest", "create", manifest); err != nil {
+		return fmt.Errorf("failed to create %s: %w", manifest, err)
+	}
+
+	// unlike docker, the create flags (e.g. --tls-verify) are given to
+	// "manifest add", which is the command that actually pulls the images.
+	for _, image := range images {
+		args := []string{"manifest", "add"}
+		args = append(args, flags...)
+		args = append(args, manifest, "docker://"+image)
+		if _, err := runCommand(ctx, ".", m.binary, args...); err != nil {
+			return fmt.Errorf("failed to add %s to %s: %w", image, manifest, err)
+		}
+	}
+	return nil
+}
+
+func (m podmanManifester) Push(ctx context.Context, manifest string, flags []string) (string, error) {
+	return withDigestFile(func(digestFile string) error {
+		args := []string{"manifest", "push", "--all", "--digestfile", digestFile}
+		args = append(args, flags...)
+		args = append(args, manifest, "docker://"+manifest)
+		if _, err := runCommand(ctx, ".", m.binary, args...); err != nil {
+			return fmt.Errorf("failed to push %s: %w", manifest, err)
+		}
+		return nil
+	})
+}
+
+// podmanImager builds and pushes images using either podman or buildah.
+type podmanImager struct {
+	binary string
+}
+
+func (i podmanImager) Push(ctx context.Context, image string, flags []string) (string, error) {
+	return withDigestFile(func(digestFile string) error {
+		args := []string{"push", "--digestfile", digestFile}
+		args = append(args, flags...)
+		args = append(args, image)
+		if _, err := runCommand(ctx, ".", i.binary, args...); err != nil {
+			return fmt.Errorf("failed to push %s: %w", image, err)
+		}
+		return nil
+	})
+}
+
+func (i podmanImager) Build(ctx context.Context, root string, images, flags []string) error {
+	if _, err := runCommand(ctx, root, i.binary, i.buildCommand(images, flags)...); err != nil {
+		return fmt.Errorf("failed to build %s: %w", images[0], err)
+	}
+	return nil
+}
+
+func (i podmanImager) buildCommand(images, flags []string) []string {
+	// buildah only got the build alias recently, bud works on all versions.
+	base := []string{"build"}
+	if i.binary == "buildah" {
+		base = []string{"bud"}
+	}
+	for _, image := range images {
+		base = append(base, "-t", image)
+	}
+	base = append(base, flags...)
+	base = append(base, ".")
+	return base
+}
+
+// withDigestFile runs fn with the path of a temporary file in which the
+// digest of the pushed image or manifest should be written, and returns its
+// contents.
+func withDigestFile(fn func(digestFile string) error) (string, error) {
+	f, err := os.CreateTemp("", "goreleaserdigest")
+	if err != nil {
+		return "", fmt.Errorf("failed to create digest file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to create digest file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	if err := fn(f.Name()); err != nil {
+		return "", err
+	}
+
+	bts, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read digest file: %w", err)
+	}
+	return strings.TrimSpace(string(bts)), nil
+}
@@ -4,10 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/goreleaser/goreleaser/internal/artifact"
@@ -19,11 +21,18 @@ import (
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
+// DigestExtra is the artifact extra holding the digest of pushed docker
+// images and manifests.
+const DigestExtra = "Digest"
+
 const (
-	dockerConfigExtra = "DockerConfig"
+	dockerConfigExtra   = "DockerConfig"
+	dockerManifestExtra = "Manifest"
 
-	useBuildx = "buildx"
-	useDocker = "docker"
+	useBuildx  = "buildx"
+	useDocker  = "docker"
+	usePodman  = "podman"
+	useBuildah = "buildah"
 )
 
 // Pipe for docker.
@@ -151,13 +160,23 @@ func process(ctx *context.Context, docker config.Docker, artifacts []*artifact.A
 	log := log.WithField("image", images[0])
 	log.Debug("tempdir: " + tmp)
 
-	if err := os.Link(docker.Dockerfile, filepath.Join(tmp, "Dockerfile")); err != nil {
+	if docker.TemplatedDockerfile {
+		if err := render(ctx, docker.Dockerfile, filepath.Join(tmp, "Dockerfile")); err != nil {
+			return fmt.Errorf("failed to render dockerfile: %w", err)
+		}
+	} else if err := os.Link(docker.Dockerfile, filepath.Join(tmp, "Dockerfile")); err != nil {
 		return fmt.Errorf("failed to link dockerfile: %w", err)
 	}
 	for _, file := range docker.Files {
 		if err := os.MkdirAll(filepath.Join(tmp, filepath.Dir(file)), 0o755); err != nil {
 			return fmt.Errorf("failed to link extra file '%s': %w", file, err)
 		}
+		if docker.TemplatedExtraFiles {
+			if err := render(ctx, file, filepath.Join(tmp, file)); err != nil {
+				return fmt.Errorf("failed to render extra file '%s': %w", file, err)
+			}
+			continue
+		}
 		if err := link(file, filepath.Join(tmp, file)); err != nil {
 			return fmt.Errorf("failed to link extra file '%s': %w", file, err)
 		}
@@ -173,6 +192,11 @@ func process(ctx *context.Context, docker config.Docker, artifacts []*artifact.A
 		return err
 	}
 
+	manifest, err := tmpl.New(ctx).Apply(docker.ManifestTemplate)
+	if err != nil {
+		return fmt.Errorf("failed to execute manifest template '%s': %w", docker.ManifestTemplate, err)
+	}
+
 	log.Info("building docker image")
 	if err := imagers[docker.Use].Build(ctx, tmp, images, buildFlags); err != nil {
 		return err
@@ -187,8 +211,8 @@ func process(ctx *context.Context, docker config.Docker, artifacts []*artifact.A
 	if strings.TrimSpace(docker.SkipPush) == "auto" && ctx.Semver.Prerelease != "" {
 		return pipe.Skip("prerelease detected with 'auto' push, skipping docker publish")
 	}
-	for _, img := range images {
-		ctx.Artifacts.Add(&artifact.Artifact{
+	for i, img := range images {
+		art := &artifact.Artifact{
 			Type:   artifact.PublishableDockerImage,
 			Name:   img,
 			Path:   img,
@@ -198,7 +222,14 @@ func process(ctx *context.Context, docker config.Docker, artifacts []*artifact.A
 			Extra: map[string]interface{}{
 				dockerConfigExtra: docker,
 			},
-		})
+		}
+		// only the first image of each docker config is added to the
+		// automatic manifest, as the others are the same image tagged
+		// differently.
+		if i == 0 && strings.TrimSpace(manifest) != "" {
+			art.Extra[dockerManifestExtra] = manifest
+		}
+		ctx.Artifacts.Add(art)
 	}
 	return nil
 }
@@ -228,6 +259,10 @@ func processImageTemplates(ctx *context.Context, docker config.Docker) ([]string
 func processBuildFlagTemplates(ctx *context.Context, docker config.Docker) ([]string, error) {
 	// nolint:prealloc
 	var buildFlags []string
+	if docker.OCILabels {
+		// added first so they can still be overridden by the user's flags.
+		buildFlags = append(buildFlags, ociLabels(ctx)...)
+	}
 	for _, buildFlagTemplate := range docker.BuildFlagTemplates {
 		buildFlag, err := tmpl.New(ctx).Apply(buildFlagTemplate)
 		if err != nil {
@@ -238,8 +273,92 @@ func processBuildFlagTemplates(ctx *context.Context, docker config.Docker) ([]st
 	return buildFlags, nil
 }
 
+// ociLabels returns the --label flags for the standard OCI image annotations
+// that can be inferred from the context.
+func ociLabels(ctx *context.Context) []string {
+	labels := [][2]string{
+		{"created", ctx.Date.UTC().Format(time.RFC3339)},
+		{"revision", ctx.Git.FullCommit},
+		{"version", ctx.Version},
+		{"source", sourceURL(ctx.Git.URL)},
+		{"title", ctx.Config.ProjectName},
+		{"licenses", license(ctx)},
+	}
+	// nolint:prealloc
+	var flags []string
+	for _, label := range labels {
+		if label[1] == "" {
+			continue
+		}
+		flags = append(flags, fmt.Sprintf("--label=org.opencontainers.image.%s=%s", label[0], label[1]))
+	}
+	return flags
+}
+
+// sourceURL converts the given git remote URL to a browsable https URL, e.g.
+// git@github.com:foo/bar.git becomes https://github.com/foo/bar. Credentials
+// in the remote, e.g. CI tokens, are removed, as the URL is published in the
+// image labels.
+func sourceURL(remote string) string {
+	source := strings.TrimSuffix(remote, ".git")
+	if strings.HasPrefix(source, "git@") {
+		source = "https://" + strings.Replace(strings.TrimPrefix(source, "git@"), ":", "/", 1)
+	}
+	u, err := url.Parse(source)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	return u.String()
+}
+
+// license returns the first license set on the project's packages, as there
+// is no project-wide license setting.
+func license(ctx *context.Context) string {
+	for _, nfpm := range ctx.Config.NFPMs {
+		if nfpm.License != "" {
+			return nfpm.License
+		}
+	}
+	for _, snap := range ctx.Config.Snapcrafts {
+		if snap.License != "" {
+			return snap.License
+		}
+	}
+	for _, brew := range ctx.Config.Brews {
+		if brew.License != "" {
+			return brew.License
+		}
+	}
+	return ctx.Config.Scoop.License
+}
+
+// walks the src, recreating dirs and writing files after applying the
+// template engine to their contents.
+func render(ctx *context.Context, src, dest string) error {
+	return walkFiles(src, dest, func(path, dst string, info os.FileInfo) error {
+		bts, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		content, err := tmpl.New(ctx).Apply(string(bts))
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(dst, []byte(content), info.Mode())
+	})
+}
+
 // walks the src, recreating dirs and hard-linking files.
 func link(src, dest string) error {
+	return walkFiles(src, dest, func(path, dst string, _ os.FileInfo) error {
+		return os.Link(path, dst)
+	})
+}
+
+// walks the src, recreating dirs and calling fn for each file with its
+// destination.
+func walkFiles(src, dest string, fn func(path, dst string, info os.FileInfo) error) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -257,23 +376,46 @@ func link(src, dest string) error {
 		if info.IsDir() {
 			return os.MkdirAll(dst, info.Mode())
 		}
-		return os.Link(path, dst)
+		return fn(path, dst, info)
 	})
 }
 
 func dockerPush(ctx *context.Context, image *artifact.Artifact) error {
 	log.WithField("image", image.Name).Info("pushing docker image")
 	docker := image.Extra[dockerConfigExtra].(config.Docker)
-	if err := imagers[docker.Use].Push(ctx, image.Name, docker.PushFlags); err != nil {
+	digest, err := imagers[docker.Use].Push(ctx, image.Name, docker.PushFlags)
+	if err != nil {
 		return err
 	}
-	ctx.Artifacts.Add(&artifact.Artifact{
+	art := &artifact.Artifact{
 		Type:   artifact.DockerImage,
 		Name:   image.Name,
 		Path:   image.Path,
 		Goarch: image.Goarch,
 		Goos:   image.Goos,
 		Goarm:  image.Goarm,
-	})
+		Extra:  map[string]interface{}{},
+	}
+	if digest != "" {
+		log.WithField("image", image.Name).WithField("digest", digest).Debug("got digest")
+		art.Extra[DigestExtra] = digest
+	}
+	ctx.Artifacts.Add(art)
 	return nil
 }
+
+// WithDigest returns a reference to the given image by its digest, dropping
+// the tag, e.g. "foo/bar:v1" becomes "foo/bar@sha256:...".
+func WithDigest(image, digest string) string {
+	if digest == "" {
+		return image
+	}
+	name := image
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	return name + "@" + digest
+}
@@ -33,7 +33,8 @@ func (ManifestPipe) Default(ctx *context.Context) error {
 			return err
 		}
 	}
-	return nil
+	_, err := expectedManifests(ctx)
+	return err
 }
 
 // Publish the docker manifests.
@@ -41,6 +42,12 @@ func (ManifestPipe) Publish(ctx *context.Context) error {
 	if ctx.SkipPublish {
 		return pipe.ErrSkipPublishEnabled
 	}
+	// the automatic manifests are checked before anything is pushed, so
+	// an invalid one doesn't leave the manifests half published.
+	autos, err := autoManifests(ctx)
+	if err != nil {
+		return err
+	}
 	g := semerrgroup.NewSkipAware(semerrgroup.New(1))
 	for _, manifest := range ctx.Config.DockerManifests {
 		manifest := manifest
@@ -63,25 +70,199 @@ func (ManifestPipe) Publish(ctx *context.Context) error {
 				return err
 			}
 
-			manifester := manifesters[manifest.Use]
+			return publishManifest(ctx, manifesters[manifest.Use], name, images, manifest.CreateFlags, manifest.PushFlags)
+		})
+	}
 
-			log.WithField("manifest", name).WithField("images", images).Info("creating docker manifest")
-			if err := manifester.Create(ctx, name, images, manifest.CreateFlags); err != nil {
-				return err
-			}
-			ctx.Artifacts.Add(&artifact.Artifact{
-				Type: artifact.DockerManifest,
-				Name: name,
-				Path: name,
-			})
-
-			log.WithField("manifest", name).Info("pushing docker manifest")
-			return manifester.Push(ctx, name, manifest.PushFlags)
+	for _, auto := range autos {
+		auto := auto
+		g.Go(func() error {
+			return publishManifest(ctx, manifesters[auto.use], auto.name, auto.images, auto.createFlags, auto.pushFlags)
 		})
 	}
 	return g.Wait()
 }
 
+func publishManifest(ctx *context.Context, manifester manifester, name string, images, createFlags, pushFlags []string) error {
+	log.WithField("manifest", name).WithField("images", images).Info("creating docker manifest")
+	if err := manifester.Create(ctx, name, images, createFlags); err != nil {
+		return err
+	}
+
+	log.WithField("manifest", name).Info("pushing docker manifest")
+	digest, err := manifester.Push(ctx, name, pushFlags)
+	if err != nil {
+		return err
+	}
+	art := &artifact.Artifact{
+		Type:  artifact.DockerManifest,
+		Name:  name,
+		Path:  name,
+		Extra: map[string]interface{}{},
+	}
+	if digest != "" {
+		art.Extra[DigestExtra] = digest
+	}
+	ctx.Artifacts.Add(art)
+	return nil
+}
+
+// autoManifest is a manifest generated from the manifest_template of the
+// docker configs.
+type autoManifest struct {
+	name        string
+	use         string
+	images      []string
+	createFlags []string
+	pushFlags   []string
+}
+
+// expectedManifest is a manifest that should be generated from the
+// manifest_template of the docker configs.
+type expectedManifest struct {
+	use       string
+	platforms []string
+}
+
+// expectedManifests groups the docker configs with a manifest_template by
+// their manifest name, making sure that every platform is listed only once and
+// that all of them use the same backend.
+func expectedManifests(ctx *context.Context) (map[string]*expectedManifest, error) {
+	expected := map[string]*expectedManifest{}
+	for _, docker := range ctx.Config.Dockers {
+		if docker.ManifestTemplate == "" {
+			continue
+		}
+		name, err := tmpl.New(ctx).Apply(docker.ManifestTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute manifest template '%s': %w", docker.ManifestTemplate, err)
+		}
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		use := manifestUse(docker.Use)
+		manifest, ok := expected[name]
+		if !ok {
+			manifest = &expectedManifest{use: use}
+			expected[name] = manifest
+		}
+		if manifest.use != use {
+			return nil, fmt.Errorf("docker manifest %s: images use both %s and %s", name, manifest.use, use)
+		}
+		platform := dockerPlatform(docker.Goos, docker.Goarch, docker.Goarm)
+		for _, p := range manifest.platforms {
+			if p == platform {
+				return nil, fmt.Errorf("docker manifest %s: platform %s is listed more than once", name, platform)
+			}
+		}
+		manifest.platforms = append(manifest.platforms, platform)
+	}
+	return expected, nil
+}
+
+// manifestUse returns the manifester of images built with the given imager.
+func manifestUse(use string) string {
+	if use == "" || use == useBuildx {
+		return useDocker
+	}
+	return use
+}
+
+// autoManifests groups the images built from docker configs with a
+// manifest_template by their manifest name, making sure that every platform
+// that should be part of each manifest was actually built.
+func autoManifests(ctx *context.Context) ([]autoManifest, error) {
+	expected, err := expectedManifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	built := map[string][]*artifact.Artifact{}
+	for _, img := range ctx.Artifacts.Filter(artifact.ByType(artifact.PublishableDockerImage)).List() {
+		name, _ := img.ExtraOr(dockerManifestExtra, "").(string)
+		if name == "" {
+			continue
+		}
+		built[name] = append(built[name], img)
+	}
+
+	digests := map[string]string{}
+	for _, img := range ctx.Artifacts.Filter(artifact.ByType(artifact.DockerImage)).List() {
+		if digest, ok := img.ExtraOr(DigestExtra, "").(string); ok && digest != "" {
+			digests[img.Name] = digest
+		}
+	}
+
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// nolint:prealloc
+	var result []autoManifest
+	for _, name := range names {
+		imgs := built[name]
+		if len(imgs) == 0 {
+			log.WithField("manifest", name).Info("no images will be pushed, skipping docker manifest")
+			continue
+		}
+
+		platforms := map[string]bool{}
+		for _, img := range imgs {
+			platforms[dockerPlatform(img.Goos, img.Goarch, img.Goarm)] = true
+		}
+		var missing []string
+		for _, platform := range expected[name].platforms {
+			if !platforms[platform] {
+				missing = append(missing, platform)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("docker manifest %s: no images were built for %s", name, strings.Join(missing, ", "))
+		}
+
+		manifest := autoManifest{
+			name: name,
+			use:  expected[name].use,
+		}
+		for _, img := range imgs {
+			manifest.images = append(manifest.images, WithDigest(img.Name, digests[img.Name]))
+			docker := img.Extra[dockerConfigExtra].(config.Docker)
+			manifest.createFlags = appendMissing(manifest.createFlags, docker.ManifestCreateFlags...)
+			manifest.pushFlags = appendMissing(manifest.pushFlags, docker.ManifestPushFlags...)
+		}
+		result = append(result, manifest)
+	}
+	return result, nil
+}
+
+// appendMissing appends the given flags which are not in flags yet, so the
+// images of a manifest can repeat the same manifest flags.
+func appendMissing(flags []string, more ...string) []string {
+	for _, flag := range more {
+		found := false
+		for _, f := range flags {
+			if f == flag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+func dockerPlatform(goos, goarch, goarm string) string {
+	platform := goos + "/" + goarch
+	if goarm != "" {
+		platform += "/v" + goarm
+	}
+	return platform
+}
+
 func validateManifester(use string) error {
 	valid := make([]string, 0, len(manifesters))
 	for k := range manifesters {
@@ -108,13 +289,22 @@ func manifestName(ctx *context.Context, manifest config.DockerManifest) (string,
 }
 
 func manifestImages(ctx *context.Context, manifest config.DockerManifest) ([]string, error) {
+	digests := map[string]string{}
+	for _, img := range ctx.Artifacts.Filter(artifact.ByType(artifact.DockerImage)).List() {
+		if digest, ok := img.ExtraOr(DigestExtra, "").(string); ok && digest != "" {
+			digests[img.Name] = digest
+		}
+	}
+
 	imgs := make([]string, 0, len(manifest.ImageTemplates))
 	for _, img := range manifest.ImageTemplates {
 		str, err := tmpl.New(ctx).Apply(img)
 		if err != nil {
 			return []string{}, err
 		}
-		imgs = append(imgs, str)
+		// reference the pushed image by its digest if we know it, so the
+		// manifest points to exactly what we just pushed.
+		imgs = append(imgs, WithDigest(str, digests[str]))
 	}
 	if strings.TrimSpace(strings.Join(manifest.ImageTemplates, "")) == "" {
 		return imgs, pipe.Skip("manifest has no images")
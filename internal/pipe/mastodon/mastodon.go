@@ -0,0 +1,62 @@
+package mastodon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/caarlos0/env/v6"
+	"github.com/goreleaser/goreleaser/internal/announcer"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+type Pipe struct{}
+
+func (Pipe) String() string { return "mastodon" }
+
+type Config struct {
+	AccessToken string `env:"MASTODON_ACCESS_TOKEN,notEmpty"`
+}
+
+func (Pipe) Default(ctx *context.Context) error {
+	if ctx.Config.Announce.Mastodon.MessageTemplate == "" {
+		ctx.Config.Announce.Mastodon.MessageTemplate = announcer.DefaultMessageTemplate
+	}
+	return nil
+}
+
+func (Pipe) Announce(ctx *context.Context) error {
+	if ctx.SkipAnnounce {
+		return pipe.ErrSkipAnnounceEnabled
+	}
+	if !ctx.Config.Announce.Mastodon.Enabled {
+		return pipe.ErrSkipDisabledPipe
+	}
+	if ctx.Config.Announce.Mastodon.Server == "" {
+		return fmt.Errorf("announce: failed to announce to mastodon: server is required")
+	}
+
+	msg, err := tmpl.New(ctx).Apply(ctx.Config.Announce.Mastodon.MessageTemplate)
+	if err != nil {
+		return fmt.Errorf("announce: failed to announce to mastodon: %w", err)
+	}
+
+	var cfg Config
+	if err := env.Parse(&cfg); err != nil {
+		return fmt.Errorf("announce: failed to announce to mastodon: %w", err)
+	}
+
+	log.Infof("posting: '%s'", msg)
+	if err := announcer.PostJSON(ctx, statusesURL(ctx), map[string]string{"status": msg}, map[string]string{
+		"Authorization": "Bearer " + cfg.AccessToken,
+	}); err != nil {
+		return fmt.Errorf("announce: failed to announce to mastodon: %w", err)
+	}
+	return nil
+}
+
+func statusesURL(ctx *context.Context) string {
+	return strings.TrimSuffix(ctx.Config.Announce.Mastodon.Server, "/") + "/api/v1/statuses"
+}
@@ -0,0 +1,142 @@
+package mastodon
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/announcer"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringer(t *testing.T) {
+	require.Equal(t, Pipe{}.String(), "mastodon")
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, ctx.Config.Announce.Mastodon.MessageTemplate, announcer.DefaultMessageTemplate)
+}
+
+func TestAnnounceDisabled(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceSkipAnnounce(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Mastodon: config.Mastodon{
+				Enabled: true,
+			},
+		},
+	})
+	ctx.SkipAnnounce = true
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceMissingServer(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Mastodon: config.Mastodon{
+				Enabled: true,
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to mastodon: server is required`)
+}
+
+func TestAnnounceInvalidTemplate(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Mastodon: config.Mastodon{
+				Enabled:         true,
+				Server:          "https://mastodon.social",
+				MessageTemplate: "{{ .Foo }",
+			},
+		},
+	})
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to mastodon: template: tmpl:1: unexpected "}" in operand`)
+}
+
+func TestAnnounceMissingEnv(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Mastodon: config.Mastodon{
+				Enabled: true,
+				Server:  "https://mastodon.social",
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to mastodon: env: environment variable "MASTODON_ACCESS_TOKEN" should not be empty`)
+}
+
+func TestAnnounce(t *testing.T) {
+	var path, auth string
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		auth = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = io.WriteString(w, `{"id": "1"}`)
+	}))
+	defer srv.Close()
+	setToken(t)
+
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Announce: config.Announce{
+			Mastodon: config.Mastodon{
+				Enabled: true,
+				Server:  srv.URL + "/",
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Git.URL = "https://github.com/foo/bar"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+	require.Equal(t, "/api/v1/statuses", path)
+	require.Equal(t, "Bearer token", auth)
+	require.Equal(t, map[string]interface{}{
+		"status": "foo v1.0.0 is out! Check it out at https://github.com/foo/bar/releases/tag/v1.0.0",
+	}, body)
+}
+
+func TestAnnounceError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = io.WriteString(w, `{"error":"The access token is invalid"}`)
+	}))
+	defer srv.Close()
+	setToken(t)
+
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Mastodon: config.Mastodon{
+				Enabled: true,
+				Server:  srv.URL,
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to mastodon: unexpected http status code 401: {"error":"The access token is invalid"}`)
+}
+
+func setToken(t *testing.T) {
+	t.Helper()
+	require.NoError(t, os.Setenv("MASTODON_ACCESS_TOKEN", "token"))
+	t.Cleanup(func() {
+		require.NoError(t, os.Unsetenv("MASTODON_ACCESS_TOKEN"))
+	})
+}
@@ -1,6 +1,8 @@
 package milestone
 
 import (
+	"fmt"
+
 	"github.com/apex/log"
 	"github.com/goreleaser/goreleaser/internal/client"
 	"github.com/goreleaser/goreleaser/internal/git"
@@ -32,6 +34,10 @@ func (Pipe) Default(ctx *context.Context) error {
 			milestone.NameTemplate = defaultNameTemplate
 		}
 
+		if milestone.MoveOpenIssues && milestone.NextNameTemplate == "" {
+			return fmt.Errorf("milestone: move_open_issues requires a next_name_template")
+		}
+
 		if milestone.Repo.Name == "" {
 			repo, err := git.ExtractRepoFromConfig()
 
@@ -76,6 +82,12 @@ func doPublish(ctx *context.Context, vcsClient client.Client) error {
 			Owner: milestone.Repo.Owner,
 		}
 
+		if milestone.NextNameTemplate != "" {
+			if err := doNext(ctx, vcsClient, milestone, repo, name); err != nil {
+				return err
+			}
+		}
+
 		log.WithField("milestone", name).
 			WithField("repo", repo.String()).
 			Info("closing milestone")
@@ -95,3 +107,49 @@ func doPublish(ctx *context.Context, vcsClient client.Client) error {
 
 	return nil
 }
+
+// doNext creates the next milestone, moving the open issues of the current one
+// to it if enabled.
+func doNext(ctx *context.Context, vcsClient client.Client, milestone *config.Milestone, repo client.Repo, name string) error {
+	next, err := tmpl.New(ctx).Apply(milestone.NextNameTemplate)
+	if err != nil {
+		return err
+	}
+
+	log.WithField("milestone", next).
+		WithField("repo", repo.String()).
+		Info("creating next milestone")
+
+	if err := vcsClient.CreateMilestone(ctx, repo, next); err != nil {
+		if milestone.FailOnError {
+			return err
+		}
+
+		log.WithField("milestone", next).
+			WithField("repo", repo.String()).
+			Warnf("error creating milestone: %s", err)
+		return nil
+	}
+
+	if !milestone.MoveOpenIssues {
+		return nil
+	}
+
+	log.WithField("milestone", name).
+		WithField("next", next).
+		WithField("repo", repo.String()).
+		Info("moving open issues")
+
+	if err := vcsClient.MoveOpenIssues(ctx, repo, name, next); err != nil {
+		if milestone.FailOnError {
+			return err
+		}
+
+		log.WithField("milestone", name).
+			WithField("next", next).
+			WithField("repo", repo.String()).
+			Warnf("error moving open issues: %s", err)
+	}
+
+	return nil
+}
@@ -185,9 +185,90 @@ func TestPublishCloseFailOnError(t *testing.T) {
 	require.Equal(t, "", client.ClosedMilestone)
 }
 
+func TestDefaultMoveOpenIssuesWithoutNext(t *testing.T) {
+	ctx := &context.Context{
+		Config: config.Project{
+			Milestones: []config.Milestone{
+				{
+					Repo:           config.Repo{Name: "configrepo", Owner: "configowner"},
+					MoveOpenIssues: true,
+				},
+			},
+		},
+	}
+	require.EqualError(t, Pipe{}.Default(ctx), "milestone: move_open_issues requires a next_name_template")
+}
+
+func nextContext(moveOpenIssues, failOnError bool) *context.Context {
+	ctx := context.New(config.Project{
+		Milestones: []config.Milestone{
+			{
+				Close:            true,
+				FailOnError:      failOnError,
+				NameTemplate:     defaultNameTemplate,
+				NextNameTemplate: "{{ incminor .Tag }}",
+				MoveOpenIssues:   moveOpenIssues,
+				Repo: config.Repo{
+					Name:  "configrepo",
+					Owner: "configowner",
+				},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.4.0"
+	return ctx
+}
+
+func TestPublishNext(t *testing.T) {
+	client := &DummyClient{}
+	require.NoError(t, doPublish(nextContext(false, false), client))
+	require.Equal(t, "v1.5.0", client.CreatedMilestone)
+	require.Equal(t, [2]string{}, client.MovedIssues)
+	require.Equal(t, "v1.4.0", client.ClosedMilestone)
+}
+
+func TestPublishNextMoveOpenIssues(t *testing.T) {
+	client := &DummyClient{}
+	require.NoError(t, doPublish(nextContext(true, false), client))
+	require.Equal(t, "v1.5.0", client.CreatedMilestone)
+	require.Equal(t, [2]string{"v1.4.0", "v1.5.0"}, client.MovedIssues)
+	require.Equal(t, "v1.4.0", client.ClosedMilestone)
+}
+
+func TestPublishNextInvalidTemplate(t *testing.T) {
+	ctx := nextContext(false, false)
+	ctx.Config.Milestones[0].NextNameTemplate = "{{ .Foo }"
+	require.EqualError(t, doPublish(ctx, &DummyClient{}), `template: tmpl:1: unexpected "}" in operand`)
+}
+
+func TestPublishNextCreateError(t *testing.T) {
+	client := &DummyClient{FailToCreateMilestone: true}
+	require.NoError(t, doPublish(nextContext(true, false), client))
+	require.Equal(t, [2]string{}, client.MovedIssues)
+	require.Equal(t, "v1.4.0", client.ClosedMilestone)
+
+	client = &DummyClient{FailToCreateMilestone: true}
+	require.EqualError(t, doPublish(nextContext(true, true), client), "milestone creation failed")
+	require.Equal(t, "", client.ClosedMilestone)
+}
+
+func TestPublishNextMoveError(t *testing.T) {
+	client := &DummyClient{FailToMoveOpenIssues: true}
+	require.NoError(t, doPublish(nextContext(true, false), client))
+	require.Equal(t, "v1.4.0", client.ClosedMilestone)
+
+	client = &DummyClient{FailToMoveOpenIssues: true}
+	require.EqualError(t, doPublish(nextContext(true, true), client), "moving issues failed")
+	require.Equal(t, "", client.ClosedMilestone)
+}
+
 type DummyClient struct {
-	ClosedMilestone      string
-	FailToCloseMilestone bool
+	ClosedMilestone       string
+	CreatedMilestone      string
+	MovedIssues           [2]string
+	FailToCloseMilestone  bool
+	FailToCreateMilestone bool
+	FailToMoveOpenIssues  bool
 }
 
 func (c *DummyClient) CloseMilestone(ctx *context.Context, repo client.Repo, title string) error {
@@ -200,6 +281,30 @@ func (c *DummyClient) CloseMilestone(ctx *context.Context, repo client.Repo, tit
 	return nil
 }
 
+func (c *DummyClient) CreateMilestone(ctx *context.Context, repo client.Repo, title string) error {
+	if c.FailToCreateMilestone {
+		return errors.New("milestone creation failed")
+	}
+
+	c.CreatedMilestone = title
+
+	return nil
+}
+
+func (c *DummyClient) MoveOpenIssues(ctx *context.Context, repo client.Repo, from, to string) error {
+	if c.FailToMoveOpenIssues {
+		return errors.New("moving issues failed")
+	}
+
+	c.MovedIssues = [2]string{from, to}
+
+	return nil
+}
+
+func (c *DummyClient) PullRequests(ctx *context.Context, repo client.Repo, commits []string) ([]client.PullRequest, error) {
+	return nil, nil
+}
+
 func (c *DummyClient) CreateRelease(ctx *context.Context, body string) (string, error) {
 	return "", nil
 }
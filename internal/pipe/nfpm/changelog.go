@@ -0,0 +1,115 @@
+package nfpm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/goreleaser/chglog"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// changelog writes the changelog of the packages of the given nfpm config in
+// the chglog format and returns its path, or an empty string if there is no
+// changelog at all.
+//
+// The entries of the current release come from the changelog pipe, and are
+// added on top of the history read from the configured changelog file.
+func changelog(ctx *context.Context, fpm config.NFPM) (string, error) {
+	history, err := changelogHistory(ctx, fpm)
+	if err != nil {
+		return "", err
+	}
+
+	entries := history
+	if len(ctx.ChangelogEntries) > 0 {
+		entry, err := changelogEntry(ctx, fpm)
+		if err != nil {
+			return "", err
+		}
+		entries = chglog.ChangeLogEntries{entry}
+		for _, entry := range history {
+			if entry.Semver == ctx.Version {
+				log.WithField("version", ctx.Version).Debug("replacing changelog history entry")
+				continue
+			}
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) == 0 {
+		return "", nil
+	}
+
+	path := filepath.Join(ctx.Config.Dist, fpm.ID+".changelog.yml")
+	log.WithField("file", path).Debug("writing package changelog")
+	if err := entries.Save(path); err != nil {
+		return "", fmt.Errorf("failed to write package changelog: %w", err)
+	}
+	return path, nil
+}
+
+func changelogHistory(ctx *context.Context, fpm config.NFPM) (chglog.ChangeLogEntries, error) {
+	file, err := tmpl.New(ctx).Apply(fpm.Changelog)
+	if err != nil {
+		return nil, err
+	}
+	if file == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(file); err != nil {
+		return nil, fmt.Errorf("failed to read changelog history: %w", err)
+	}
+	return chglog.Parse(file)
+}
+
+var (
+	commitRe = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+	// scmSuffixRe matches the author and link of the pull request entries,
+	// e.g. " by @foo in [#1](https://github.com/foo/bar/pull/1)".
+	scmSuffixRe = regexp.MustCompile(` by @\S+$| (by @\S+ )?in \[[#!]\d+\]\([^)]*\)$`)
+)
+
+// changelogEntry creates the changelog entry of the current release from the
+// changelog, which lines are in the "<sha> <message>" format. The author and
+// link of the pull requests are removed when the changelog is built from them.
+func changelogEntry(ctx *context.Context, fpm config.NFPM) (*chglog.ChangeLog, error) {
+	distribution, err := tmpl.New(ctx).Apply(fpm.ChangelogDistribution)
+	if err != nil {
+		return nil, fmt.Errorf("failed to template changelog distribution: %w", err)
+	}
+	urgency, err := tmpl.New(ctx).Apply(fpm.ChangelogUrgency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to template changelog urgency: %w", err)
+	}
+	entry := &chglog.ChangeLog{
+		ChangeLogOverridables: chglog.ChangeLogOverridables{
+			Deb: &chglog.ChangelogDeb{
+				Urgency:       urgency,
+				Distributions: []string{distribution},
+			},
+		},
+		Semver:   ctx.Version,
+		Date:     ctx.Date,
+		Packager: fpm.Maintainer,
+	}
+	scm := ctx.Config.Changelog.Use != "" && ctx.Config.Changelog.Use != "git"
+	for _, line := range ctx.ChangelogEntries {
+		if scm {
+			line = scmSuffixRe.ReplaceAllString(line, "")
+		}
+		commit, note := "", line
+		if i := strings.Index(line, " "); i > 0 && commitRe.MatchString(line[:i]) {
+			commit, note = line[:i], line[i+1:]
+		}
+		entry.Changes = append(entry.Changes, &chglog.ChangeLogChange{
+			Commit: commit,
+			Note:   note,
+		})
+	}
+	return entry, nil
+}
@@ -0,0 +1,285 @@
+package nfpm
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/goreleaser/nfpm/v2/files"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/linux"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/semerrgroup"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// nolint: gochecknoglobals
+var (
+	debNameRe     = regexp.MustCompile(`^[a-z0-9][a-z0-9+.-]+$`)
+	debVersionRe  = regexp.MustCompile(`^([0-9]+:)?[0-9][A-Za-z0-9.+~-]*$`)
+	debRelationRe = regexp.MustCompile(`^[a-z0-9][a-z0-9+.-]+(:[a-z0-9-]+)?(\s*\(\s*(<<|<=|=|>=|>>)\s*([0-9]+:)?[0-9][A-Za-z0-9.+~-]*\s*\))?(\s*\[[^\]]+\])?$`)
+
+	rpmNameRe    = regexp.MustCompile(`^[A-Za-z0-9._+-]+$`)
+	rpmVersionRe = regexp.MustCompile(`^[A-Za-z0-9._+~^]+$`)
+	rpmRelNameRe = regexp.MustCompile(`^[^\s,<>=]+$`)
+	rpmEVRRe     = regexp.MustCompile(`^[^\s(),<>=]+$`)
+)
+
+// nolint: gochecknoglobals
+var debRelationFields = []string{
+	"Pre-Depends", "Depends", "Recommends", "Suggests", "Enhances",
+	"Breaks", "Conflicts", "Replaces", "Provides",
+}
+
+// LintPipe reads the generated deb and rpm packages back and checks that they
+// are well formed and have the configured contents.
+type LintPipe struct{}
+
+func (LintPipe) String() string {
+	return "linting linux packages"
+}
+
+// Run the pipe.
+func (LintPipe) Run(ctx *context.Context) error {
+	var ids []string
+	for _, fpm := range ctx.Config.NFPMs {
+		if fpm.Lint {
+			ids = append(ids, fpm.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return pipe.ErrSkipDisabledPipe
+	}
+
+	g := semerrgroup.New(ctx.Parallelism)
+	for _, pkg := range ctx.Artifacts.Filter(artifact.And(
+		artifact.ByType(artifact.LinuxPackage),
+		artifact.ByIDs(ids...),
+	)).List() {
+		pkg := pkg
+		g.Go(func() error {
+			return lint(pkg)
+		})
+	}
+	return g.Wait()
+}
+
+func lint(pkg *artifact.Artifact) error {
+	format := pkg.ExtraOr("Format", "").(string)
+	var problems []string
+	var err error
+	switch format {
+	case "deb":
+		problems, err = lintDeb(pkg)
+	case "rpm":
+		problems, err = lintRPM(pkg)
+	default:
+		log.WithField("package", pkg.Name).Debugf("linting %s packages is not supported", format)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s is not valid:\n\t- %s", pkg.Name, strings.Join(problems, "\n\t- "))
+	}
+	log.WithField("package", pkg.Name).Debug("package is valid")
+	return nil
+}
+
+func lintDeb(pkg *artifact.Artifact) ([]string, error) {
+	deb, err := linux.ReadDeb(pkg.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, field := range []string{"Package", "Version", "Architecture", "Maintainer", "Description"} {
+		if deb.Control.Get(field) == "" {
+			problems = append(problems, fmt.Sprintf("missing %s field", field))
+		}
+	}
+	if name := deb.Control.Get("Package"); name != "" && !debNameRe.MatchString(name) {
+		problems = append(problems, fmt.Sprintf("invalid package name %q", name))
+	}
+	if version := deb.Control.Get("Version"); version != "" && !debVersionRe.MatchString(version) {
+		problems = append(problems, fmt.Sprintf("invalid version %q", version))
+	}
+	for _, field := range debRelationFields {
+		value := deb.Control.Get(field)
+		if value == "" {
+			continue
+		}
+		for _, alternatives := range strings.Split(value, ",") {
+			for _, rel := range strings.Split(alternatives, "|") {
+				rel = strings.TrimSpace(rel)
+				if !debRelationRe.MatchString(rel) {
+					problems = append(problems, fmt.Sprintf("invalid %s entry %q", field, rel))
+				}
+			}
+		}
+	}
+
+	entries := map[string]packageFile{}
+	for _, f := range deb.Files {
+		entries[f.Path] = packageFile{mode: f.Mode, linkname: f.Linkname}
+	}
+	contentProblems, err := lintContents(pkg, "deb", entries)
+	if err != nil {
+		return nil, err
+	}
+	return append(problems, contentProblems...), nil
+}
+
+func lintRPM(pkg *artifact.Artifact) ([]string, error) {
+	rpm, err := linux.ReadRPM(pkg.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	for _, tag := range []struct{ name, value string }{
+		{"Name", rpm.Name},
+		{"Version", rpm.Version},
+		{"Release", rpm.Release},
+		{"Arch", rpm.Arch},
+		{"Summary", rpm.Summary},
+		{"License", rpm.License},
+	} {
+		if tag.value == "" {
+			problems = append(problems, fmt.Sprintf("missing %s tag", tag.name))
+		}
+	}
+	if rpm.Name != "" && !rpmNameRe.MatchString(rpm.Name) {
+		problems = append(problems, fmt.Sprintf("invalid package name %q", rpm.Name))
+	}
+	if rpm.Version != "" && !rpmVersionRe.MatchString(rpm.Version) {
+		problems = append(problems, fmt.Sprintf("invalid version %q", rpm.Version))
+	}
+	if rpm.Release != "" && !rpmVersionRe.MatchString(rpm.Release) {
+		problems = append(problems, fmt.Sprintf("invalid release %q", rpm.Release))
+	}
+	for _, relations := range []struct {
+		kind    string
+		entries []linux.RPMRelation
+	}{
+		{"Provides", rpm.Provides},
+		{"Requires", rpm.Requires},
+		{"Conflicts", rpm.Conflicts},
+		{"Obsoletes", rpm.Obsoletes},
+	} {
+		for _, rel := range relations.entries {
+			if problem := lintRPMRelation(rel); problem != "" {
+				problems = append(problems, fmt.Sprintf("invalid %s entry %q: %s", relations.kind, rpmRelationString(rel), problem))
+			}
+		}
+	}
+
+	entries := map[string]packageFile{}
+	for _, f := range rpm.Files {
+		entries[path.Clean(f.Path)] = packageFile{mode: f.Mode, linkname: f.Linkname}
+	}
+	contentProblems, err := lintContents(pkg, "rpm", entries)
+	if err != nil {
+		return nil, err
+	}
+	return append(problems, contentProblems...), nil
+}
+
+func lintRPMRelation(rel linux.RPMRelation) string {
+	if !rpmRelNameRe.MatchString(rel.Name) || strings.Count(rel.Name, "(") != strings.Count(rel.Name, ")") {
+		return "invalid name"
+	}
+	evr := rel.Version
+	if rel.Release != "" {
+		evr += "-" + rel.Release
+	}
+	if rel.Flags == "" && evr != "" {
+		return "version without a comparison operator"
+	}
+	if rel.Flags != "" && (rel.Version == "" || !rpmEVRRe.MatchString(evr)) {
+		return "invalid version"
+	}
+	return ""
+}
+
+func rpmRelationString(rel linux.RPMRelation) string {
+	s := rel.Name
+	if rel.Flags != "" {
+		s += " " + rel.Flags
+	}
+	if rel.Version != "" {
+		s += " " + rel.Version
+	}
+	if rel.Release != "" {
+		s += "-" + rel.Release
+	}
+	return s
+}
+
+// packageFile is a file read back from a package.
+type packageFile struct {
+	mode     os.FileMode
+	linkname string
+}
+
+// lintContents checks that the configured contents and binaries were added to
+// the package with the expected modes.
+func lintContents(pkg *artifact.Artifact, format string, entries map[string]packageFile) ([]string, error) {
+	var contents files.Contents
+	for _, content := range pkg.ExtraOr("Files", files.Contents{}).(files.Contents) {
+		if content.Packager != "" && content.Packager != format {
+			continue
+		}
+		if content.Type == "ghost" && format != "rpm" {
+			continue
+		}
+		contents = append(contents, content)
+	}
+	contents, err := files.ExpandContentGlobs(contents, false)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to expand contents: %w", pkg.Name, err)
+	}
+
+	binaries := map[string]bool{}
+	for _, binary := range pkg.ExtraOr("Builds", []*artifact.Artifact{}).([]*artifact.Artifact) {
+		binaries[files.ToNixPath(binary.Path)] = true
+	}
+
+	var problems []string
+	for _, content := range contents {
+		dst := path.Clean("/" + content.Destination)
+		entry, ok := entries[dst]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s is missing", dst))
+			continue
+		}
+		switch content.Type {
+		case "symlink":
+			if entry.mode&os.ModeSymlink == 0 {
+				problems = append(problems, fmt.Sprintf("%s should be a symlink", dst))
+			} else if entry.linkname != content.Source {
+				problems = append(problems, fmt.Sprintf("%s links to %s, expected %s", dst, entry.linkname, content.Source))
+			}
+		case "ghost":
+			// ghost files are not in the package payload, so there is
+			// nothing else to check.
+		default:
+			if !entry.mode.IsRegular() {
+				problems = append(problems, fmt.Sprintf("%s should be a regular file, got %s", dst, entry.mode))
+				continue
+			}
+			if want := content.FileInfo.Mode.Perm(); want != 0 && entry.mode.Perm() != want {
+				problems = append(problems, fmt.Sprintf("%s has mode %04o, expected %04o", dst, entry.mode.Perm(), want))
+			}
+			if binaries[files.ToNixPath(content.Source)] && entry.mode&0o111 == 0 {
+				problems = append(problems, fmt.Sprintf("binary %s is not executable", dst))
+			}
+		}
+	}
+	return problems, nil
+}
@@ -0,0 +1,181 @@
+package nfpm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/goreleaser/nfpm/v2/files"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintDescription(t *testing.T) {
+	require.NotEmpty(t, LintPipe{}.String())
+}
+
+func TestLintDisabled(t *testing.T) {
+	ctx := context.New(config.Project{
+		NFPMs: []config.NFPM{{ID: "foo"}},
+	})
+	testlib.AssertSkipped(t, LintPipe{}.Run(ctx))
+}
+
+func TestLint(t *testing.T) {
+	ctx := lintContext(t, 0o755, "make", "git")
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.NoError(t, LintPipe{}.Run(ctx))
+}
+
+func TestLintDebDependencies(t *testing.T) {
+	ctx := lintContext(t, 0o755, "libc6 (>= 2.17) | musl", "python3:any", "bash (<< 1:6.0~rc1)")
+	ctx.Config.NFPMs[0].Formats = []string{"deb"}
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.NoError(t, LintPipe{}.Run(ctx))
+}
+
+func TestLintInvalidDependency(t *testing.T) {
+	ctx := lintContext(t, 0o755, "libc6 (>= 2.17")
+	ctx.Config.NFPMs[0].Formats = []string{"deb"}
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.EqualError(t, LintPipe{}.Run(ctx), "foo_1.0.0_linux_amd64.deb is not valid:\n\t- invalid Depends entry \"libc6 (>= 2.17\"")
+}
+
+func TestLintInvalidRPMDependency(t *testing.T) {
+	ctx := lintContext(t, 0o755, "libc6 (>= 2.17)")
+	ctx.Config.NFPMs[0].Formats = []string{"rpm"}
+	require.NoError(t, Pipe{}.Run(ctx))
+	err := LintPipe{}.Run(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "foo_1.0.0_linux_amd64.rpm is not valid:\n\t- invalid Requires entry")
+}
+
+func TestLintBinaryNotExecutable(t *testing.T) {
+	ctx := lintContext(t, 0o644)
+	require.NoError(t, Pipe{}.Run(ctx))
+	for _, format := range []string{"deb", "rpm"} {
+		t.Run(format, func(t *testing.T) {
+			pkg := ctx.Artifacts.Filter(artifact.ByFormats(format)).List()[0]
+			require.EqualError(t, lint(pkg), pkg.Name+" is not valid:\n\t- binary /usr/bin/mybin is not executable")
+		})
+	}
+}
+
+func TestLintContents(t *testing.T) {
+	ctx := lintContext(t, 0o755)
+	require.NoError(t, Pipe{}.Run(ctx))
+	for _, format := range []string{"deb", "rpm"} {
+		t.Run(format, func(t *testing.T) {
+			pkg := ctx.Artifacts.Filter(artifact.ByFormats(format)).List()[0]
+			contents := pkg.ExtraOr("Files", files.Contents{}).(files.Contents)
+			var changed files.Contents
+			for _, content := range contents {
+				if content.Destination == "/etc/foo.conf" {
+					content = &files.Content{
+						Source:      content.Source,
+						Destination: content.Destination,
+						Type:        content.Type,
+						FileInfo:    &files.ContentFileInfo{Mode: 0o600},
+					}
+				}
+				if content.Destination == "/etc/bar.conf" {
+					content = &files.Content{
+						Source:      "/etc/other.conf",
+						Destination: content.Destination,
+						Type:        content.Type,
+					}
+				}
+				changed = append(changed, content)
+			}
+			pkg.Extra["Files"] = append(
+				changed,
+				&files.Content{
+					Source:      "./testdata/testfile.txt",
+					Destination: "/usr/share/nope.txt",
+				},
+			)
+			require.EqualError(t, lint(pkg), pkg.Name+" is not valid:"+
+				"\n\t- /usr/share/nope.txt is missing"+
+				"\n\t- /etc/foo.conf has mode 0644, expected 0600"+
+				"\n\t- /etc/bar.conf links to /etc/foo.conf, expected /etc/other.conf")
+		})
+	}
+}
+
+func TestLintUnsupportedFormat(t *testing.T) {
+	require.NoError(t, lint(&artifact.Artifact{
+		Name: "foo.apk",
+		Extra: map[string]interface{}{
+			"Format": "apk",
+		},
+	}))
+}
+
+func TestLintInvalidPackage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foo.deb")
+	require.NoError(t, os.WriteFile(path, []byte("nope"), 0o644))
+	require.EqualError(t, lint(&artifact.Artifact{
+		Name: "foo.deb",
+		Path: path,
+		Extra: map[string]interface{}{
+			"Format": "deb",
+		},
+	}), "failed to read deb: "+path+": not an ar archive")
+}
+
+func lintContext(t *testing.T, mode os.FileMode, deps ...string) *context.Context {
+	t.Helper()
+	folder := t.TempDir()
+	binPath := filepath.Join(folder, "mybin")
+	require.NoError(t, os.WriteFile(binPath, []byte("#!/bin/sh"), mode))
+	ctx := context.New(config.Project{
+		ProjectName: "mybin",
+		Dist:        folder,
+		NFPMs: []config.NFPM{
+			{
+				ID:          "someid",
+				Bindir:      "/usr/bin",
+				Builds:      []string{"default"},
+				Formats:     []string{"deb", "rpm"},
+				Description: "Some description",
+				License:     "MIT",
+				Maintainer:  "me@me",
+				Lint:        true,
+				NFPMOverridables: config.NFPMOverridables{
+					FileNameTemplate: defaultNameTemplate,
+					PackageName:      "foo",
+					Dependencies:     deps,
+					Contents: []*files.Content{
+						{
+							Source:      "./testdata/testfile.txt",
+							Destination: "/etc/foo.conf",
+							Type:        "config",
+							FileInfo:    &files.ContentFileInfo{Mode: 0o644},
+						},
+						{
+							Source:      "/etc/foo.conf",
+							Destination: "/etc/bar.conf",
+							Type:        "symlink",
+						},
+					},
+				},
+			},
+		},
+	})
+	ctx.Version = "1.0.0"
+	ctx.Git = context.GitInfo{CurrentTag: "v1.0.0"}
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   "mybin",
+		Path:   binPath,
+		Goarch: "amd64",
+		Goos:   "linux",
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			"ID": "default",
+		},
+	})
+	return ctx
+}
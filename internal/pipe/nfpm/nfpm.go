@@ -51,6 +51,12 @@ func (Pipe) Default(ctx *context.Context) error {
 		if fpm.FileNameTemplate == "" {
 			fpm.FileNameTemplate = defaultNameTemplate
 		}
+		if fpm.ChangelogDistribution == "" {
+			fpm.ChangelogDistribution = "stable"
+		}
+		if fpm.ChangelogUrgency == "" {
+			fpm.ChangelogUrgency = "low"
+		}
 		if len(fpm.Builds) == 0 { // TODO: change this to empty by default and deal with it in the filtering code
 			for _, b := range ctx.Config.Builds {
 				fpm.Builds = append(fpm.Builds, b.ID)
@@ -84,6 +90,10 @@ func doRun(ctx *context.Context, fpm config.NFPM) error {
 	if len(linuxBinaries) == 0 {
 		return fmt.Errorf("no linux binaries found for builds %v", fpm.Builds)
 	}
+	changelog, err := changelog(ctx, fpm)
+	if err != nil {
+		return err
+	}
 	g := semerrgroup.New(ctx.Parallelism)
 	for _, format := range fpm.Formats {
 		for platform, artifacts := range linuxBinaries {
@@ -91,7 +101,7 @@ func doRun(ctx *context.Context, fpm config.NFPM) error {
 			arch := linux.Arch(platform)
 			artifacts := artifacts
 			g.Go(func() error {
-				return create(ctx, fpm, format, arch, artifacts)
+				return create(ctx, fpm, format, arch, changelog, artifacts)
 			})
 		}
 	}
@@ -113,7 +123,7 @@ func mergeOverrides(fpm config.NFPM, format string) (*config.NFPMOverridables, e
 	return &overridden, nil
 }
 
-func create(ctx *context.Context, fpm config.NFPM, format, arch string, binaries []*artifact.Artifact) error {
+func create(ctx *context.Context, fpm config.NFPM, format, arch, changelog string, binaries []*artifact.Artifact) error {
 	overridden, err := mergeOverrides(fpm, format)
 	if err != nil {
 		return err
@@ -191,6 +201,7 @@ func create(ctx *context.Context, fpm config.NFPM, format, arch string, binaries
 		Vendor:          fpm.Vendor,
 		Homepage:        homepage,
 		License:         fpm.License,
+		Changelog:       changelog,
 		Overridables: nfpm.Overridables{
 			Conflicts:    overridden.Conflicts,
 			Depends:      overridden.Dependencies,
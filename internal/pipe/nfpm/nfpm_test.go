@@ -7,6 +7,7 @@ import (
 	"runtime"
 	"testing"
 
+	"github.com/goreleaser/chglog"
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
@@ -410,6 +411,8 @@ func TestDefault(t *testing.T) {
 	require.Equal(t, []string{"foo", "bar"}, ctx.Config.NFPMs[0].Builds)
 	require.Equal(t, defaultNameTemplate, ctx.Config.NFPMs[0].FileNameTemplate)
 	require.Equal(t, ctx.Config.ProjectName, ctx.Config.NFPMs[0].PackageName)
+	require.Equal(t, "stable", ctx.Config.NFPMs[0].ChangelogDistribution)
+	require.Equal(t, "low", ctx.Config.NFPMs[0].ChangelogUrgency)
 }
 
 func TestDefaultSet(t *testing.T) {
@@ -1041,3 +1044,146 @@ func sources(contents files.Contents) []string {
 	}
 	return result
 }
+
+func TestRunPipeWithChangelog(t *testing.T) {
+	dist := t.TempDir()
+	ctx := context.New(config.Project{
+		ProjectName: "mybin",
+		Dist:        dist,
+		NFPMs: []config.NFPM{
+			{
+				ID:          "someid",
+				Builds:      []string{"default"},
+				Formats:     []string{"deb", "rpm"},
+				Description: "Some description",
+				License:     "MIT",
+				Maintainer:  "me@me",
+				Changelog:   "./testdata/{{ .Env.HISTORY }}",
+				NFPMOverridables: config.NFPMOverridables{
+					FileNameTemplate: defaultNameTemplate,
+					PackageName:      "foo",
+				},
+			},
+		},
+	})
+	ctx.Version = "1.0.0"
+	ctx.Git = context.GitInfo{CurrentTag: "v1.0.0"}
+	ctx.Env["HISTORY"] = "changelog.yml"
+	ctx.ChangelogEntries = []string{"abcdef1 added feature 1", "1234567 fixed bug 2"}
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name:   "mybin",
+		Path:   "testdata/testfile.txt",
+		Goarch: "amd64",
+		Goos:   "linux",
+		Type:   artifact.Binary,
+		Extra: map[string]interface{}{
+			"ID": "default",
+		},
+	})
+	require.NoError(t, Pipe{}.Run(ctx))
+	require.Len(t, ctx.Artifacts.Filter(artifact.ByType(artifact.LinuxPackage)).List(), 2)
+
+	entries, err := chglog.Parse(filepath.Join(dist, "someid.changelog.yml"))
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, "1.0.0", entries[0].Semver)
+	require.Equal(t, "me@me", entries[0].Packager)
+	require.Equal(t, ctx.Date.Unix(), entries[0].Date.Unix())
+	require.Len(t, entries[0].Changes, 2)
+	require.Equal(t, "abcdef1", entries[0].Changes[0].Commit)
+	require.Equal(t, "added feature 1", entries[0].Changes[0].Note)
+	require.Equal(t, "0.9.0", entries[1].Semver)
+	require.Equal(t, "0.1.0", entries[2].Semver)
+}
+
+func TestChangelogOnlyHistory(t *testing.T) {
+	ctx := context.New(config.Project{Dist: t.TempDir()})
+	ctx.Version = "0.9.0"
+	ctx.ChangelogEntries = []string{"abcdef1 fixed it"}
+	path, err := changelog(ctx, config.NFPM{ID: "foo", Changelog: "testdata/changelog.yml"})
+	require.NoError(t, err)
+	entries, err := chglog.Parse(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2, "entry of the same version should be replaced")
+	require.Equal(t, "fixed it", entries[0].Changes[0].Note)
+
+	ctx.ChangelogEntries = nil
+	path, err = changelog(ctx, config.NFPM{ID: "foo", Changelog: "testdata/changelog.yml"})
+	require.NoError(t, err)
+	entries, err = chglog.Parse(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "first release", entries[0].Changes[0].Note)
+}
+
+func TestChangelogDebOptions(t *testing.T) {
+	ctx := context.New(config.Project{Dist: t.TempDir()})
+	ctx.Version = "1.0.0"
+	ctx.Semver.Prerelease = "rc1"
+	ctx.ChangelogEntries = []string{"abcdef1 fixed it"}
+	path, err := changelog(ctx, config.NFPM{
+		ID:                    "foo",
+		ChangelogDistribution: "{{ if .Prerelease }}unstable{{ else }}stable{{ end }}",
+		ChangelogUrgency:      "medium",
+	})
+	require.NoError(t, err)
+	entries, err := chglog.Parse(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, []string{"unstable"}, entries[0].Deb.Distributions)
+	require.Equal(t, "medium", entries[0].Deb.Urgency)
+
+	_, err = changelog(ctx, config.NFPM{ID: "foo", ChangelogDistribution: "{{ .Nope }"})
+	require.Error(t, err)
+	_, err = changelog(ctx, config.NFPM{ID: "foo", ChangelogUrgency: "{{ .Nope }"})
+	require.Error(t, err)
+}
+
+func TestChangelogSCMEntries(t *testing.T) {
+	ctx := context.New(config.Project{
+		Dist:      t.TempDir(),
+		Changelog: config.Changelog{Use: "github"},
+	})
+	ctx.Version = "1.0.0"
+	ctx.ChangelogEntries = []string{
+		"abc1234 feat: foo by @someone in [#1](https://github.com/foo/bar/pull/1)",
+		"def5678 fix: bar in [!2](https://gitlab.com/foo/bar/-/merge_requests/2)",
+		"1234567 docs: by @nobody",
+		"#3 chore: baz",
+	}
+	path, err := changelog(ctx, config.NFPM{ID: "foo"})
+	require.NoError(t, err)
+	entries, err := chglog.Parse(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	require.Equal(t, chglog.ChangeLogChanges{
+		{Commit: "abc1234", Note: "feat: foo"},
+		{Commit: "def5678", Note: "fix: bar"},
+		{Commit: "1234567", Note: "docs:"},
+		{Note: "#3 chore: baz"},
+	}, entries[0].Changes)
+
+	ctx.Config.Changelog.Use = "git"
+	ctx.ChangelogEntries = []string{"abc1234 thanks to the work by @someone"}
+	path, err = changelog(ctx, config.NFPM{ID: "foo"})
+	require.NoError(t, err)
+	entries, err = chglog.Parse(path)
+	require.NoError(t, err)
+	require.Equal(t, "thanks to the work by @someone", entries[0].Changes[0].Note)
+}
+
+func TestChangelogEmpty(t *testing.T) {
+	ctx := context.New(config.Project{Dist: t.TempDir()})
+	path, err := changelog(ctx, config.NFPM{ID: "foo"})
+	require.NoError(t, err)
+	require.Empty(t, path)
+}
+
+func TestChangelogHistoryErrors(t *testing.T) {
+	ctx := context.New(config.Project{Dist: t.TempDir()})
+	_, err := changelog(ctx, config.NFPM{ID: "foo", Changelog: "testdata/nope.yml"})
+	require.EqualError(t, err, "failed to read changelog history: stat testdata/nope.yml: no such file or directory")
+
+	_, err = changelog(ctx, config.NFPM{ID: "foo", Changelog: "{{ .Nope }"})
+	require.Error(t, err)
+}
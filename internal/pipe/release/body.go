@@ -6,6 +6,7 @@ import (
 	"text/template"
 
 	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/pipe/docker"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
@@ -27,6 +28,13 @@ func isLatest(img string) bool {
 	return strings.HasSuffix(img, ":latest") || !strings.Contains(img, ":")
 }
 
+// pullName returns the name to be used to pull the given docker image or
+// manifest, pinning it to its digest if known.
+func pullName(a *artifact.Artifact) string {
+	digest, _ := a.ExtraOr(docker.DigestExtra, "").(string)
+	return docker.WithDigest(a.Name, digest)
+}
+
 func describeBody(ctx *context.Context) (bytes.Buffer, error) {
 	var out bytes.Buffer
 	// nolint:prealloc
@@ -35,14 +43,14 @@ func describeBody(ctx *context.Context) (bytes.Buffer, error) {
 		if isLatest(a.Name) {
 			continue
 		}
-		dockers = append(dockers, a.Name)
+		dockers = append(dockers, pullName(a))
 	}
 	if len(dockers) == 0 {
 		for _, a := range ctx.Artifacts.Filter(artifact.ByType(artifact.DockerImage)).List() {
 			if isLatest(a.Name) {
 				continue
 			}
-			dockers = append(dockers, a.Name)
+			dockers = append(dockers, pullName(a))
 		}
 	}
 
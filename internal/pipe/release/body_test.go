@@ -64,6 +64,27 @@ func TestDescribeBodyWithDockerManifest(t *testing.T) {
 	golden.RequireEqual(t, out.Bytes())
 }
 
+func TestDescribeBodyWithDigests(t *testing.T) {
+	changelog := "feature1: description\nfeature2: other description"
+	ctx := context.New(config.Project{})
+	ctx.ReleaseNotes = changelog
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "goreleaser/goreleaser:0.40.0",
+		Type: artifact.DockerManifest,
+		Extra: map[string]interface{}{
+			"Digest": "sha256:0e5bd6cc5ea3ab0b6c4c1e5e2cbbd8e6d2f0d4d3de0e06e55b0d2c2a6bbf1a7b",
+		},
+	})
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Name: "goreleaser/godownloader:v0.1.0",
+		Type: artifact.DockerManifest,
+	})
+	out, err := describeBody(ctx)
+	require.NoError(t, err)
+
+	golden.RequireEqual(t, out.Bytes())
+}
+
 func TestDescribeBodyNoDockerImagesNoBrews(t *testing.T) {
 	changelog := "feature1: description\nfeature2: other description"
 	ctx := &context.Context{
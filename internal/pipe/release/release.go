@@ -47,6 +47,14 @@ func (Pipe) Default(ctx *context.Context) error {
 		ctx.Config.Release.NameTemplate = "{{.Tag}}"
 	}
 
+	switch ctx.Config.Release.Mode {
+	case "":
+		ctx.Config.Release.Mode = client.ReleaseModeKeepExisting
+	case client.ReleaseModeKeepExisting, client.ReleaseModeAppend, client.ReleaseModePrepend, client.ReleaseModeReplace:
+	default:
+		return fmt.Errorf("release: invalid mode: %s", ctx.Config.Release.Mode)
+	}
+
 	// nolint: exhaustive
 	switch ctx.TokenType {
 	case context.TokenTypeGitLab:
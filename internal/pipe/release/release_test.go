@@ -336,6 +336,34 @@ func TestDefault(t *testing.T) {
 	require.NoError(t, Pipe{}.Default(ctx))
 	require.Equal(t, "goreleaser", ctx.Config.Release.GitHub.Name)
 	require.Equal(t, "goreleaser", ctx.Config.Release.GitHub.Owner)
+	require.Equal(t, "keep-existing", ctx.Config.Release.Mode)
+}
+
+func TestDefaultMode(t *testing.T) {
+	for _, mode := range []string{"keep-existing", "append", "prepend", "replace"} {
+		t.Run(mode, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				Release: config.Release{
+					GitHub: config.Repo{Owner: "owner", Name: "name"},
+					Mode:   mode,
+				},
+			})
+			ctx.TokenType = context.TokenTypeGitHub
+			require.NoError(t, Pipe{}.Default(ctx))
+			require.Equal(t, mode, ctx.Config.Release.Mode)
+		})
+	}
+}
+
+func TestDefaultInvalidMode(t *testing.T) {
+	ctx := context.New(config.Project{
+		Release: config.Release{
+			GitHub: config.Repo{Owner: "owner", Name: "name"},
+			Mode:   "overwrite",
+		},
+	})
+	ctx.TokenType = context.TokenTypeGitHub
+	require.EqualError(t, Pipe{}.Default(ctx), "release: invalid mode: overwrite")
 }
 
 func TestDefaultWithGitlab(t *testing.T) {
@@ -536,6 +564,18 @@ func (c *DummyClient) CloseMilestone(ctx *context.Context, repo client.Repo, tit
 	return nil
 }
 
+func (c *DummyClient) CreateMilestone(ctx *context.Context, repo client.Repo, title string) error {
+	return nil
+}
+
+func (c *DummyClient) MoveOpenIssues(ctx *context.Context, repo client.Repo, from, to string) error {
+	return nil
+}
+
+func (c *DummyClient) PullRequests(ctx *context.Context, repo client.Repo, commits []string) ([]client.PullRequest, error) {
+	return nil, nil
+}
+
 func (c *DummyClient) CreateRelease(ctx *context.Context, body string) (releaseID string, err error) {
 	if c.FailToCreateRelease {
 		return "", errors.New("release failed")
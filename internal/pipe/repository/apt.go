@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/linux"
+)
+
+// apt generates the apt repository metadata for the given deb packages,
+// following https://wiki.debian.org/DebianRepository/Format.
+func (r *repository) apt(debs []*artifact.Artifact) error {
+	conf := r.conf.APT
+	dists := path.Join("dists", conf.Suite)
+
+	existing, err := r.existingAPT()
+	if err != nil {
+		return err
+	}
+
+	// arch -> package stanzas
+	indexes := map[string][]linux.Paragraph{}
+	var all []linux.Paragraph
+	for _, deb := range debs {
+		stanza, err := r.aptPackage(deb)
+		if err != nil {
+			return err
+		}
+		arch := stanza.Get("Architecture")
+		if arch == "all" {
+			all = append(all, stanza)
+			continue
+		}
+		indexes[arch] = append(indexes[arch], stanza)
+	}
+
+	archs := map[string]bool{}
+	for arch := range indexes {
+		archs[arch] = true
+	}
+	for arch := range existing {
+		archs[arch] = true
+	}
+	if len(archs) == 0 {
+		return fmt.Errorf("no architecture specific deb packages found, can't create an apt repository with only 'all' packages")
+	}
+
+	var files []string
+	for _, arch := range sortedKeys(archs) {
+		stanzas := mergeStanzas(existing[arch], append(indexes[arch], all...))
+		var buf bytes.Buffer
+		for i, stanza := range stanzas {
+			if i > 0 {
+				buf.WriteString("\n")
+			}
+			buf.WriteString(stanza.String())
+		}
+
+		name := path.Join(conf.Component, "binary-"+arch, "Packages")
+		gz, err := gzipBytes(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		if err := r.writeFile(path.Join(dists, name), buf.Bytes()); err != nil {
+			return err
+		}
+		if err := r.writeFile(path.Join(dists, name+".gz"), gz); err != nil {
+			return err
+		}
+		files = append(files, name, name+".gz")
+	}
+
+	release, err := r.aptRelease(dists, sortedKeys(archs), files)
+	if err != nil {
+		return err
+	}
+	if err := r.writeFile(path.Join(dists, "Release"), release); err != nil {
+		return err
+	}
+	if r.signer == nil {
+		return nil
+	}
+	inRelease, err := r.signer.clearSign(release)
+	if err != nil {
+		return err
+	}
+	if err := r.writeFile(path.Join(dists, "InRelease"), inRelease); err != nil {
+		return err
+	}
+	releaseGPG, err := r.signer.detachSign(release)
+	if err != nil {
+		return err
+	}
+	return r.writeFile(path.Join(dists, "Release.gpg"), releaseGPG)
+}
+
+// aptPackage adds the given deb to the pool and returns its Packages stanza.
+func (r *repository) aptPackage(deb *artifact.Artifact) (linux.Paragraph, error) {
+	info, err := linux.ReadDeb(deb.Path)
+	if err != nil {
+		return nil, err
+	}
+	stanza := info.Control
+	name := stanza.Get("Package")
+	if name == "" {
+		return nil, fmt.Errorf("%s: missing Package field", deb.Name)
+	}
+
+	prefix := name[:1]
+	if strings.HasPrefix(name, "lib") && len(name) > 3 {
+		prefix = name[:4]
+	}
+	filename := path.Join("pool", r.conf.APT.Component, prefix, name, deb.Name)
+	if err := r.addPackage(deb, filename); err != nil {
+		return nil, err
+	}
+
+	hashes, err := hashFile(deb.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checksum %s: %w", deb.Name, err)
+	}
+	stanza.Set("Filename", filename)
+	stanza.Set("Size", fmt.Sprint(hashes.Size))
+	stanza.Set("MD5sum", hashes.MD5)
+	stanza.Set("SHA1", hashes.SHA1)
+	stanza.Set("SHA256", hashes.SHA256)
+	return stanza, nil
+}
+
+// existingAPT returns the package stanzas of the existing repository by
+// architecture.
+func (r *repository) existingAPT() (map[string][]linux.Paragraph, error) {
+	conf := r.conf.APT
+	dists := path.Join("dists", conf.Suite)
+	result := map[string][]linux.Paragraph{}
+
+	bts, err := r.existing.Read(r.ctx, path.Join(dists, "Release"))
+	if err != nil || bts == nil {
+		return result, err
+	}
+	release, err := linux.ParseParagraphs(bytes.NewReader(bts))
+	if err != nil {
+		return nil, fmt.Errorf("invalid existing Release file: %w", err)
+	}
+	if len(release) != 1 {
+		return nil, fmt.Errorf("invalid existing Release file: expected 1 paragraph, got %d", len(release))
+	}
+
+	for _, arch := range strings.Fields(release[0].Get("Architectures")) {
+		bts, err := r.existing.Read(r.ctx, path.Join(dists, conf.Component, "binary-"+arch, "Packages"))
+		if err != nil {
+			return nil, err
+		}
+		if bts == nil {
+			continue
+		}
+		stanzas, err := linux.ParseParagraphs(bytes.NewReader(bts))
+		if err != nil {
+			return nil, fmt.Errorf("invalid existing Packages file for %s: %w", arch, err)
+		}
+		result[arch] = stanzas
+	}
+	return result, nil
+}
+
+// mergeStanzas merges the new package stanzas into the existing ones,
+// replacing packages with the same name, version and architecture.
+func mergeStanzas(existing, added []linux.Paragraph) []linux.Paragraph {
+	key := func(p linux.Paragraph) string {
+		return p.Get("Package") + " " + p.Get("Version") + " " + p.Get("Architecture")
+	}
+	index := map[string]int{}
+	result := make([]linux.Paragraph, 0, len(existing)+len(added))
+	for _, p := range existing {
+		index[key(p)] = len(result)
+		result = append(result, p)
+	}
+	for _, p := range added {
+		if i, ok := index[key(p)]; ok {
+			result[i] = p
+			continue
+		}
+		index[key(p)] = len(result)
+		result = append(result, p)
+	}
+	return result
+}
+
+func (r *repository) aptRelease(dists string, archs, files []string) ([]byte, error) {
+	conf := r.conf.APT
+	release := linux.Paragraph{}
+	for _, f := range []linux.Field{
+		{Name: "Origin", Value: conf.Origin},
+		{Name: "Label", Value: conf.Label},
+		{Name: "Suite", Value: conf.Suite},
+		{Name: "Codename", Value: conf.Suite},
+		{Name: "Date", Value: r.ctx.Date.UTC().Format(time.RFC1123)},
+		{Name: "Architectures", Value: strings.Join(archs, " ")},
+		{Name: "Components", Value: conf.Component},
+		{Name: "Description", Value: conf.Description},
+	} {
+		if f.Value != "" {
+			release = append(release, f)
+		}
+	}
+
+	var md5s, sha1s, sha256s strings.Builder
+	for _, name := range files {
+		hashes, err := hashFile(r.pathOf(path.Join(dists, name)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to checksum %s: %w", name, err)
+		}
+		fmt.Fprintf(&md5s, "\n %s %d %s", hashes.MD5, hashes.Size, name)
+		fmt.Fprintf(&sha1s, "\n %s %d %s", hashes.SHA1, hashes.Size, name)
+		fmt.Fprintf(&sha256s, "\n %s %d %s", hashes.SHA256, hashes.Size, name)
+	}
+	release.Set("MD5Sum", md5s.String())
+	release.Set("SHA1", sha1s.String())
+	release.Set("SHA256", sha256s.String())
+	return []byte(release.String()), nil
+}
+
+func gzipBytes(bts []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	if _, err := io.Copy(gw, bytes.NewReader(bts)); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"gocloud.dev/blob"
+	"gocloud.dev/gcerrors"
+
+	// Import the blob packages we want to be able to open.
+	_ "gocloud.dev/blob/azureblob"
+	"gocloud.dev/blob/fileblob"
+	_ "gocloud.dev/blob/gcsblob"
+	_ "gocloud.dev/blob/s3blob"
+)
+
+// existing provides the metadata files of the already published repository,
+// so new packages can be merged into it.
+type existing interface {
+	// Read returns the contents of the given file, or nil if it does not
+	// exist.
+	Read(ctx *context.Context, name string) ([]byte, error)
+	Close() error
+}
+
+// noExisting is used when there is no previously published repository.
+type noExisting struct{}
+
+func (noExisting) Read(*context.Context, string) ([]byte, error) { return nil, nil }
+func (noExisting) Close() error                                  { return nil }
+
+// bucketExisting reads the existing metadata from a blob bucket or local
+// directory.
+type bucketExisting struct {
+	bucket *blob.Bucket
+}
+
+func (e bucketExisting) Read(ctx *context.Context, name string) ([]byte, error) {
+	bts, err := e.bucket.ReadAll(ctx, name)
+	if gcerrors.Code(err) == gcerrors.NotFound {
+		log.WithField("file", name).Debug("not found in existing repository")
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read existing %s: %w", name, err)
+	}
+	return bts, nil
+}
+
+func (e bucketExisting) Close() error {
+	return e.bucket.Close()
+}
+
+// openExisting opens the repository's existing location, which can either be
+// a local directory or a bucket URL, e.g. s3://bucket/folder?region=us-east-1.
+func openExisting(ctx *context.Context, repo config.Repository) (existing, error) {
+	location, err := tmpl.New(ctx).Apply(repo.Existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to template existing location: %w", err)
+	}
+	if location == "" {
+		return noExisting{}, nil
+	}
+
+	if !strings.Contains(location, "://") {
+		if _, err := os.Stat(location); os.IsNotExist(err) {
+			log.WithField("dir", location).Warn("existing repository not found, creating a new one")
+			return noExisting{}, nil
+		}
+		bucket, err := fileblob.OpenBucket(location, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open existing repository: %w", err)
+		}
+		return bucketExisting{bucket: bucket}, nil
+	}
+
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open existing repository: %w", err)
+	}
+	prefix := strings.Trim(u.Path, "/")
+	u.Path = ""
+	bucket, err := blob.OpenBucket(ctx, u.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open existing repository: %w", err)
+	}
+	if prefix != "" {
+		bucket = blob.PrefixedBucket(bucket, prefix+"/")
+	}
+	return bucketExisting{bucket: bucket}, nil
+}
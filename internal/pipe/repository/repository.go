@@ -0,0 +1,224 @@
+// Package repository provides a pipe that generates APT and YUM repository
+// metadata for the linux packages built by nfpm.
+package repository
+
+import (
+	"crypto/md5"  // nolint:gosec
+	"crypto/sha1" // nolint:gosec
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/apex/log"
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/ids"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// Pipe for linux package repositories.
+type Pipe struct{}
+
+func (Pipe) String() string {
+	return "linux package repositories"
+}
+
+// Default sets the pipe defaults.
+func (Pipe) Default(ctx *context.Context) error {
+	ids := ids.New("repositories")
+	for i := range ctx.Config.Repositories {
+		repo := &ctx.Config.Repositories[i]
+		if repo.ID == "" {
+			repo.ID = "default"
+		}
+		if repo.APT.Suite == "" {
+			repo.APT.Suite = "stable"
+		}
+		if repo.APT.Component == "" {
+			repo.APT.Component = "main"
+		}
+		if repo.APT.Origin == "" {
+			repo.APT.Origin = ctx.Config.ProjectName
+		}
+		if repo.APT.Label == "" {
+			repo.APT.Label = ctx.Config.ProjectName
+		}
+		ids.Inc(repo.ID)
+	}
+	return ids.Validate()
+}
+
+// Run the pipe.
+func (Pipe) Run(ctx *context.Context) error {
+	if len(ctx.Config.Repositories) == 0 {
+		return pipe.ErrSkipDisabledPipe
+	}
+	for _, repo := range ctx.Config.Repositories {
+		if err := doRun(ctx, repo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func doRun(ctx *context.Context, repo config.Repository) error {
+	filters := []artifact.Filter{artifact.ByType(artifact.LinuxPackage)}
+	if len(repo.IDs) > 0 {
+		filters = append(filters, artifact.ByIDs(repo.IDs...))
+	}
+	packages := ctx.Artifacts.Filter(artifact.And(filters...))
+	debs := packages.Filter(artifact.ByFormats("deb")).List()
+	rpms := packages.Filter(artifact.ByFormats("rpm")).List()
+	if len(debs) == 0 && len(rpms) == 0 {
+		return pipe.Skip(fmt.Sprintf("no deb or rpm packages found for repository %s", repo.ID))
+	}
+
+	existing, err := openExisting(ctx, repo)
+	if err != nil {
+		return err
+	}
+	defer existing.Close()
+
+	signer, err := newSigner(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	r := &repository{
+		ctx:      ctx,
+		conf:     repo,
+		root:     filepath.Join(ctx.Config.Dist, "repositories", repo.ID),
+		existing: existing,
+		signer:   signer,
+	}
+	if err := os.MkdirAll(r.root, 0o755); err != nil {
+		return fmt.Errorf("failed to create repository folder: %w", err)
+	}
+
+	if len(debs) > 0 {
+		log.WithField("repository", repo.ID).Info("generating apt repository")
+		if err := r.apt(debs); err != nil {
+			return fmt.Errorf("repository %s: %w", repo.ID, err)
+		}
+	}
+	if len(rpms) > 0 {
+		log.WithField("repository", repo.ID).Info("generating yum repository")
+		if err := r.yum(rpms); err != nil {
+			return fmt.Errorf("repository %s: %w", repo.ID, err)
+		}
+	}
+	return nil
+}
+
+// repository holds the state needed to generate a single repository.
+type repository struct {
+	ctx      *context.Context
+	conf     config.Repository
+	root     string
+	existing existing
+	signer   *signer
+}
+
+// addPackage links the given package into the repository tree and adds it as
+// an artifact.
+func (r *repository) addPackage(pkg *artifact.Artifact, name string) error {
+	path := r.pathOf(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to add %s: %w", pkg.Name, err)
+	}
+	_ = os.Remove(path)
+	if err := os.Link(pkg.Path, path); err != nil {
+		return fmt.Errorf("failed to add %s: %w", pkg.Name, err)
+	}
+	r.addArtifact(name, path)
+	return nil
+}
+
+// writeFile writes a metadata file into the repository tree and adds it as
+// an artifact.
+func (r *repository) writeFile(name string, content []byte) error {
+	path := r.pathOf(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	log.WithField("file", path).Debug("writing")
+	if err := os.WriteFile(path, content, 0o644); err != nil { //nolint: gosec
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	r.addArtifact(name, path)
+	return nil
+}
+
+// pathOf returns the local path of the given file of the repository tree.
+func (r *repository) pathOf(name string) string {
+	return filepath.Join(r.root, filepath.FromSlash(name))
+}
+
+func (r *repository) addArtifact(name, path string) {
+	r.ctx.Artifacts.Add(&artifact.Artifact{
+		Type: artifact.RepositoryFile,
+		Name: name,
+		Path: path,
+		Extra: map[string]interface{}{
+			"ID": r.conf.ID,
+		},
+	})
+}
+
+// fileHashes holds the size and checksums of a file.
+type fileHashes struct {
+	Size   int64
+	MD5    string
+	SHA1   string
+	SHA256 string
+}
+
+// nolint:gosec
+func hashFile(path string) (fileHashes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return fileHashes{}, err
+	}
+	defer f.Close()
+	m, s1, s256 := md5.New(), sha1.New(), sha256.New()
+	size, err := io.Copy(io.MultiWriter(m, s1, s256), f)
+	if err != nil {
+		return fileHashes{}, err
+	}
+	return fileHashes{
+		Size:   size,
+		MD5:    hex.EncodeToString(m.Sum(nil)),
+		SHA1:   hex.EncodeToString(s1.Sum(nil)),
+		SHA256: hex.EncodeToString(s256.Sum(nil)),
+	}, nil
+}
+
+// nolint:gosec
+func hashBytes(bts []byte) fileHashes {
+	m, s1, s256 := md5.Sum(bts), sha1.Sum(bts), sha256.Sum256(bts)
+	return fileHashes{
+		Size:   int64(len(bts)),
+		MD5:    hex.EncodeToString(m[:]),
+		SHA1:   hex.EncodeToString(s1[:]),
+		SHA256: hex.EncodeToString(s256[:]),
+	}
+}
+
+func sortedKeys(m map[string]bool) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func passphrase(ctx *context.Context, repo config.Repository) string {
+	return ctx.Env[fmt.Sprintf("REPOSITORY_%s_PASSPHRASE", strings.ToUpper(repo.ID))]
+}
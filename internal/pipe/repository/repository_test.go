@@ -0,0 +1,391 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/linux"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/goreleaser/nfpm/v2"
+	_ "github.com/goreleaser/nfpm/v2/deb" // blank import to register the format
+	"github.com/goreleaser/nfpm/v2/files"
+	_ "github.com/goreleaser/nfpm/v2/rpm" // blank import to register the format
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescription(t *testing.T) {
+	require.NotEmpty(t, Pipe{}.String())
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.New(config.Project{
+		ProjectName: "mybin",
+		Repositories: []config.Repository{
+			{},
+			{
+				ID: "other",
+				APT: config.APTRepository{
+					Suite:     "unstable",
+					Component: "contrib",
+					Origin:    "Foo",
+					Label:     "Bar",
+				},
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, config.Repository{
+		ID: "default",
+		APT: config.APTRepository{
+			Suite:     "stable",
+			Component: "main",
+			Origin:    "mybin",
+			Label:     "mybin",
+		},
+	}, ctx.Config.Repositories[0])
+	require.Equal(t, config.APTRepository{
+		Suite:     "unstable",
+		Component: "contrib",
+		Origin:    "Foo",
+		Label:     "Bar",
+	}, ctx.Config.Repositories[1].APT)
+}
+
+func TestDefaultDuplicatedIDs(t *testing.T) {
+	ctx := context.New(config.Project{
+		Repositories: []config.Repository{{}, {}},
+	})
+	require.EqualError(t, Pipe{}.Default(ctx), "found 2 repositories with the ID 'default', please fix your config")
+}
+
+func TestRunDisabled(t *testing.T) {
+	testlib.AssertSkipped(t, Pipe{}.Run(context.New(config.Project{})))
+}
+
+func TestRunNoPackages(t *testing.T) {
+	ctx := testContext(t, config.Repository{})
+	testlib.AssertSkipped(t, Pipe{}.Run(ctx))
+}
+
+func TestRunAPT(t *testing.T) {
+	ctx := testContext(t, config.Repository{})
+	addPackage(t, ctx, "deb", "1.0.0", "amd64")
+	addPackage(t, ctx, "deb", "1.0.0", "arm64")
+	addPackage(t, ctx, "rpm", "1.0.0", "amd64")
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	root := filepath.Join(ctx.Config.Dist, "repositories", "default")
+	require.FileExists(t, filepath.Join(root, "pool/main/m/mybin/mybin_1.0.0_amd64.deb"))
+	require.FileExists(t, filepath.Join(root, "pool/main/m/mybin/mybin_1.0.0_arm64.deb"))
+	require.NoFileExists(t, filepath.Join(root, "dists/stable/InRelease"))
+
+	stanzas := readPackages(t, root, "amd64")
+	require.Len(t, stanzas, 1)
+	require.Equal(t, "mybin", stanzas[0].Get("Package"))
+	require.Equal(t, "pool/main/m/mybin/mybin_1.0.0_amd64.deb", stanzas[0].Get("Filename"))
+	require.NotEmpty(t, stanzas[0].Get("SHA256"))
+
+	gz, err := os.ReadFile(filepath.Join(root, "dists/stable/main/binary-arm64/Packages.gz"))
+	require.NoError(t, err)
+	plain, err := os.ReadFile(filepath.Join(root, "dists/stable/main/binary-arm64/Packages"))
+	require.NoError(t, err)
+	require.Equal(t, string(plain), gunzip(t, gz))
+
+	release := readRelease(t, root)
+	require.Equal(t, "mybin", release.Get("Origin"))
+	require.Equal(t, "stable", release.Get("Suite"))
+	require.Equal(t, "amd64 arm64", release.Get("Architectures"))
+	require.Equal(t, "main", release.Get("Components"))
+	require.Equal(t, "Wed, 02 Jun 2021 10:00:00 UTC", release.Get("Date"))
+	require.Contains(t, release.Get("SHA256"), " main/binary-amd64/Packages\n")
+	require.Contains(t, release.Get("SHA256"), " main/binary-arm64/Packages.gz")
+
+	var names []string
+	for _, a := range ctx.Artifacts.Filter(artifact.ByType(artifact.RepositoryFile)).List() {
+		require.Equal(t, "default", a.ExtraOr("ID", ""))
+		names = append(names, a.Name)
+	}
+	require.ElementsMatch(t, []string{
+		"pool/main/m/mybin/mybin_1.0.0_amd64.deb",
+		"pool/main/m/mybin/mybin_1.0.0_arm64.deb",
+		"dists/stable/main/binary-amd64/Packages",
+		"dists/stable/main/binary-amd64/Packages.gz",
+		"dists/stable/main/binary-arm64/Packages",
+		"dists/stable/main/binary-arm64/Packages.gz",
+		"dists/stable/Release",
+		"Packages/mybin-1.0.0.x86_64.rpm",
+		"repodata/repomd.xml",
+	}, filterMetadata(names))
+}
+
+func TestRunAPTOnlyAll(t *testing.T) {
+	ctx := testContext(t, config.Repository{})
+	addPackage(t, ctx, "deb", "1.0.0", "all")
+	require.EqualError(t, Pipe{}.Run(ctx), "repository default: no architecture specific deb packages found, can't create an apt repository with only 'all' packages")
+}
+
+func TestRunYUM(t *testing.T) {
+	ctx := testContext(t, config.Repository{})
+	addPackage(t, ctx, "rpm", "1.0.0", "amd64")
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	root := filepath.Join(ctx.Config.Dist, "repositories", "default")
+	require.FileExists(t, filepath.Join(root, "Packages/mybin-1.0.0.x86_64.rpm"))
+	require.NoFileExists(t, filepath.Join(root, "repodata/repomd.xml.asc"))
+
+	repomd := readRepomd(t, root)
+	require.Equal(t, "1622628000", repomd.Revision)
+	require.Len(t, repomd.Data, 3)
+	for i, kind := range []string{"primary", "filelists", "other"} {
+		data := repomd.Data[i]
+		require.Equal(t, kind, data.Type)
+		require.Equal(t, "repodata/"+data.Checksum.Value+"-"+kind+".xml.gz", data.Location.Href)
+		require.Equal(t, int64(1622628000), data.Timestamp)
+	}
+
+	primary := readYUMData(t, root, repomd.Data[0])
+	require.Contains(t, primary, `<metadata xmlns="http://linux.duke.edu/metadata/common" xmlns:rpm="http://linux.duke.edu/metadata/rpm" packages="1">`)
+	require.Contains(t, primary, `<name>mybin</name><arch>x86_64</arch><version epoch="0" ver="1.0.0" rel="1"></version>`)
+	require.Contains(t, primary, `<location href="Packages/mybin-1.0.0.x86_64.rpm"></location>`)
+	require.Contains(t, primary, `<rpm:license>MIT</rpm:license>`)
+	require.Contains(t, primary, `<rpm:entry name="git"></rpm:entry>`)
+	require.Contains(t, primary, `<file>/usr/bin/mybin</file>`)
+	require.NotContains(t, primary, `/usr/share/man/man1/mybin.1`)
+
+	filelists := readYUMData(t, root, repomd.Data[1])
+	require.Contains(t, filelists, `name="mybin" arch="x86_64"`)
+	require.Contains(t, filelists, `<file>/usr/share/man/man1/mybin.1</file>`)
+
+	other := readYUMData(t, root, repomd.Data[2])
+	require.Contains(t, other, `<otherdata xmlns="http://linux.duke.edu/metadata/other" packages="1">`)
+}
+
+func TestRunMergeExisting(t *testing.T) {
+	existing := t.TempDir()
+	ctx := testContext(t, config.Repository{Existing: existing})
+	addPackage(t, ctx, "deb", "1.0.0", "amd64")
+	addPackage(t, ctx, "rpm", "1.0.0", "amd64")
+	require.NoError(t, Pipe{}.Run(ctx))
+	copyDir(t, filepath.Join(ctx.Config.Dist, "repositories", "default"), existing)
+
+	ctx = testContext(t, config.Repository{Existing: existing})
+	addPackage(t, ctx, "deb", "1.1.0", "amd64")
+	addPackage(t, ctx, "deb", "1.1.0", "all")
+	addPackage(t, ctx, "rpm", "1.1.0", "amd64")
+	addPackage(t, ctx, "rpm", "1.0.0", "amd64")
+	require.NoError(t, Pipe{}.Run(ctx))
+	root := filepath.Join(ctx.Config.Dist, "repositories", "default")
+
+	var versions []string
+	for _, stanza := range readPackages(t, root, "amd64") {
+		versions = append(versions, stanza.Get("Version")+" "+stanza.Get("Architecture"))
+	}
+	require.Equal(t, []string{"1.0.0 amd64", "1.1.0 amd64", "1.1.0 all"}, versions)
+
+	repomd := readRepomd(t, root)
+	for _, data := range repomd.Data {
+		content := readYUMData(t, root, data)
+		require.Contains(t, content, `packages="2"`)
+		require.Equal(t, 1, strings.Count(content, `<version epoch="0" ver="1.0.0"`), data.Type)
+		require.Equal(t, 1, strings.Count(content, `<version epoch="0" ver="1.1.0"`), data.Type)
+	}
+}
+
+func TestRunMissingExisting(t *testing.T) {
+	ctx := testContext(t, config.Repository{Existing: filepath.Join(t.TempDir(), "nope")})
+	addPackage(t, ctx, "deb", "1.0.0", "amd64")
+	require.NoError(t, Pipe{}.Run(ctx))
+}
+
+func TestRunInvalidExistingRelease(t *testing.T) {
+	existing := t.TempDir()
+	dists := filepath.Join(existing, "dists", "stable")
+	require.NoError(t, os.MkdirAll(dists, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dists, "Release"), []byte("Suite: stable\n\nSuite: other\n"), 0o644))
+
+	ctx := testContext(t, config.Repository{Existing: existing})
+	addPackage(t, ctx, "deb", "1.0.0", "amd64")
+	err := Pipe{}.Run(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid existing Release file: expected 1 paragraph, got 2")
+}
+
+func TestRunSigned(t *testing.T) {
+	ctx := testContext(t, config.Repository{
+		Signature: config.RepositorySignature{KeyFile: "./testdata/privkey.gpg"},
+	})
+	ctx.Env["REPOSITORY_DEFAULT_PASSPHRASE"] = "hunter2"
+	addPackage(t, ctx, "deb", "1.0.0", "amd64")
+	addPackage(t, ctx, "rpm", "1.0.0", "amd64")
+	require.NoError(t, Pipe{}.Run(ctx))
+
+	root := filepath.Join(ctx.Config.Dist, "repositories", "default")
+	inRelease, err := os.ReadFile(filepath.Join(root, "dists/stable/InRelease"))
+	require.NoError(t, err)
+	require.Contains(t, string(inRelease), "-----BEGIN PGP SIGNED MESSAGE-----")
+	for _, name := range []string{"dists/stable/Release.gpg", "repodata/repomd.xml.asc"} {
+		sig, err := os.ReadFile(filepath.Join(root, name))
+		require.NoError(t, err)
+		require.Contains(t, string(sig), "-----BEGIN PGP SIGNATURE-----")
+	}
+}
+
+func TestRunSignedWrongPassphrase(t *testing.T) {
+	ctx := testContext(t, config.Repository{
+		Signature: config.RepositorySignature{KeyFile: "./testdata/privkey.gpg"},
+	})
+	addPackage(t, ctx, "deb", "1.0.0", "amd64")
+	require.EqualError(t, Pipe{}.Run(ctx), "repository default: signing key is encrypted but no passphrase was provided")
+
+	ctx.Env["REPOSITORY_DEFAULT_PASSPHRASE"] = "nope"
+	require.Error(t, Pipe{}.Run(ctx))
+}
+
+func TestRunInvalidTemplates(t *testing.T) {
+	for name, repo := range map[string]config.Repository{
+		"existing": {Existing: "{{ .Nope }"},
+		"key file": {Signature: config.RepositorySignature{KeyFile: "{{ .Nope }"}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := testContext(t, repo)
+			addPackage(t, ctx, "deb", "1.0.0", "amd64")
+			require.Error(t, Pipe{}.Run(ctx))
+		})
+	}
+}
+
+func testContext(t *testing.T, repo config.Repository) *context.Context {
+	t.Helper()
+	ctx := context.New(config.Project{
+		ProjectName:  "mybin",
+		Dist:         t.TempDir(),
+		Repositories: []config.Repository{repo},
+	})
+	ctx.Date = time.Date(2021, 6, 2, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, Pipe{}.Default(ctx))
+	return ctx
+}
+
+func addPackage(t *testing.T, ctx *context.Context, format, version, arch string) {
+	t.Helper()
+	info := nfpm.WithDefaults(&nfpm.Info{
+		Name:        "mybin",
+		Arch:        arch,
+		Platform:    "linux",
+		Version:     version,
+		Maintainer:  "Foo Bar <foo@bar>",
+		Description: "My binary.",
+		License:     "MIT",
+		Overridables: nfpm.Overridables{
+			Depends: []string{"git"},
+			Contents: files.Contents{
+				{Source: "./testdata/mybin", Destination: "/usr/bin/mybin"},
+				{Source: "./testdata/mybin", Destination: "/usr/share/man/man1/mybin.1"},
+			},
+		},
+	})
+	packager, err := nfpm.Get(format)
+	require.NoError(t, err)
+	name := packager.ConventionalFileName(info)
+	path := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	require.NoError(t, packager.Package(info, f))
+	require.NoError(t, f.Close())
+	ctx.Artifacts.Add(&artifact.Artifact{
+		Type: artifact.LinuxPackage,
+		Name: name,
+		Path: path,
+		Extra: map[string]interface{}{
+			"ID":     "default",
+			"Format": format,
+		},
+	})
+}
+
+func readPackages(t *testing.T, root, arch string) []linux.Paragraph {
+	t.Helper()
+	f, err := os.Open(filepath.Join(root, "dists/stable/main/binary-"+arch+"/Packages"))
+	require.NoError(t, err)
+	defer f.Close()
+	stanzas, err := linux.ParseParagraphs(f)
+	require.NoError(t, err)
+	return stanzas
+}
+
+func readRelease(t *testing.T, root string) linux.Paragraph {
+	t.Helper()
+	f, err := os.Open(filepath.Join(root, "dists/stable/Release"))
+	require.NoError(t, err)
+	defer f.Close()
+	release, err := linux.ParseParagraphs(f)
+	require.NoError(t, err)
+	require.Len(t, release, 1)
+	return release[0]
+}
+
+func readRepomd(t *testing.T, root string) yumRepomd {
+	t.Helper()
+	bts, err := os.ReadFile(filepath.Join(root, "repodata/repomd.xml"))
+	require.NoError(t, err)
+	var repomd yumRepomd
+	require.NoError(t, xml.Unmarshal(bts, &repomd))
+	return repomd
+}
+
+func readYUMData(t *testing.T, root string, data yumRepomdData) string {
+	t.Helper()
+	bts, err := os.ReadFile(filepath.Join(root, data.Location.Href))
+	require.NoError(t, err)
+	require.Equal(t, data.Checksum.Value, hashBytes(bts).SHA256)
+	content := gunzip(t, bts)
+	require.Equal(t, data.OpenChecksum.Value, hashBytes([]byte(content)).SHA256)
+	return content
+}
+
+func gunzip(t *testing.T, bts []byte) string {
+	t.Helper()
+	gr, err := gzip.NewReader(bytes.NewReader(bts))
+	require.NoError(t, err)
+	plain, err := io.ReadAll(gr)
+	require.NoError(t, err)
+	return string(plain)
+}
+
+// filterMetadata removes the yum data files, which have checksums in their
+// names, from the given list.
+func filterMetadata(names []string) []string {
+	var result []string
+	for _, name := range names {
+		if strings.HasSuffix(name, ".xml.gz") {
+			continue
+		}
+		result = append(result, name)
+	}
+	return result
+}
+
+func copyDir(t *testing.T, src, dst string) {
+	t.Helper()
+	require.NoError(t, filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		require.NoError(t, err)
+		rel, err := filepath.Rel(src, path)
+		require.NoError(t, err)
+		if info.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0o755)
+		}
+		bts, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return os.WriteFile(filepath.Join(dst, rel), bts, 0o644)
+	}))
+}
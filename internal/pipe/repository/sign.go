@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/clearsign"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+// signer signs the repository metadata with a PGP key.
+type signer struct {
+	entity *openpgp.Entity
+}
+
+// newSigner returns the signer for the given repository, or nil if it
+// should not be signed.
+func newSigner(ctx *context.Context, repo config.Repository) (*signer, error) {
+	keyFile, err := tmpl.New(ctx).Apply(repo.Signature.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to template key file: %w", err)
+	}
+	if keyFile == "" {
+		return nil, nil
+	}
+
+	entity, err := readSigningKey(keyFile, passphrase(ctx, repo))
+	if err != nil {
+		return nil, fmt.Errorf("repository %s: %w", repo.ID, err)
+	}
+	return &signer{entity: entity}, nil
+}
+
+func readSigningKey(keyFile, passphrase string) (*openpgp.Entity, error) {
+	bts, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(bts))
+	if err != nil {
+		entities, err = openpgp.ReadKeyRing(bytes.NewReader(bts))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+	for _, entity := range entities {
+		if entity.PrivateKey == nil {
+			continue
+		}
+		keys := []*packet.PrivateKey{entity.PrivateKey}
+		for _, subkey := range entity.Subkeys {
+			if subkey.PrivateKey != nil {
+				keys = append(keys, subkey.PrivateKey)
+			}
+		}
+		for _, key := range keys {
+			if !key.Encrypted {
+				continue
+			}
+			if passphrase == "" {
+				return nil, errors.New("signing key is encrypted but no passphrase was provided")
+			}
+			if err := key.Decrypt([]byte(passphrase)); err != nil {
+				return nil, fmt.Errorf("failed to decrypt signing key: %w", err)
+			}
+		}
+		return entity, nil
+	}
+	return nil, errors.New("no private key found in signing key file")
+}
+
+// clearSign returns the given data with an inline signature, as used by
+// InRelease files.
+func (s *signer) clearSign(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := clearsign.Encode(&buf, s.entity.PrivateKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// detachSign returns an armored detached signature of the given data.
+func (s *signer) detachSign(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&buf, s.entity, bytes.NewReader(data), nil); err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,453 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/goreleaser/goreleaser/internal/artifact"
+	"github.com/goreleaser/goreleaser/internal/linux"
+)
+
+const (
+	yumNamespaceCommon    = "http://linux.duke.edu/metadata/common"
+	yumNamespaceRPM       = "http://linux.duke.edu/metadata/rpm"
+	yumNamespaceFilelists = "http://linux.duke.edu/metadata/filelists"
+	yumNamespaceOther     = "http://linux.duke.edu/metadata/other"
+	yumNamespaceRepo      = "http://linux.duke.edu/metadata/repo"
+)
+
+// files that are listed in primary.xml, the rest is only in filelists.xml.
+var yumPrimaryFilesRe = regexp.MustCompile(`^(/etc/|.*bin/|/usr/lib/sendmail$)`)
+
+// yumMetadata is one of the metadata files of a yum repository.
+type yumMetadata struct {
+	kind      string // primary, filelists or other
+	root      string // root element name
+	namespace string
+}
+
+// nolint:gochecknoglobals
+var yumMetadatas = []yumMetadata{
+	{kind: "primary", root: "metadata", namespace: `xmlns="` + yumNamespaceCommon + `" xmlns:rpm="` + yumNamespaceRPM + `"`},
+	{kind: "filelists", root: "filelists", namespace: `xmlns="` + yumNamespaceFilelists + `"`},
+	{kind: "other", root: "otherdata", namespace: `xmlns="` + yumNamespaceOther + `"`},
+}
+
+// yumEntry is a package entry in one of the metadata files, kept as raw XML
+// so existing entries can be merged without losing any information.
+type yumEntry struct {
+	key string
+	xml []byte
+}
+
+// yum generates the yum/dnf repository metadata for the given rpm packages,
+// following https://docs.pulpproject.org/en/2.9/plugins/pulp_rpm/tech-reference/yum-plugins.html
+// and what createrepo_c generates.
+func (r *repository) yum(rpms []*artifact.Artifact) error {
+	existing, err := r.existingYUM()
+	if err != nil {
+		return err
+	}
+
+	added := map[string][]yumEntry{}
+	for _, rpm := range rpms {
+		entries, err := r.yumPackage(rpm)
+		if err != nil {
+			return err
+		}
+		for kind, entry := range entries {
+			added[kind] = append(added[kind], entry)
+		}
+	}
+
+	repomd := yumRepomd{
+		Xmlns:    yumNamespaceRepo,
+		XmlnsRPM: yumNamespaceRPM,
+		Revision: fmt.Sprint(r.ctx.Date.Unix()),
+	}
+	for _, md := range yumMetadatas {
+		entries := mergeYUMEntries(existing[md.kind], added[md.kind])
+		var buf bytes.Buffer
+		buf.WriteString(xml.Header)
+		fmt.Fprintf(&buf, "<%s %s packages=\"%d\">\n", md.root, md.namespace, len(entries))
+		for _, entry := range entries {
+			buf.Write(entry.xml)
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "</%s>\n", md.root)
+
+		gz, err := gzipBytes(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		open := hashBytes(buf.Bytes())
+		compressed := hashBytes(gz)
+		name := path.Join("repodata", compressed.SHA256+"-"+md.kind+".xml.gz")
+		if err := r.writeFile(name, gz); err != nil {
+			return err
+		}
+		repomd.Data = append(repomd.Data, yumRepomdData{
+			Type:         md.kind,
+			Checksum:     yumChecksum{Type: "sha256", Value: compressed.SHA256},
+			OpenChecksum: yumChecksum{Type: "sha256", Value: open.SHA256},
+			Location:     yumLocation{Href: name},
+			Timestamp:    r.ctx.Date.Unix(),
+			Size:         compressed.Size,
+			OpenSize:     open.Size,
+		})
+	}
+
+	bts, err := xml.MarshalIndent(repomd, "", "  ")
+	if err != nil {
+		return err
+	}
+	bts = append([]byte(xml.Header), append(bts, '\n')...)
+	if err := r.writeFile("repodata/repomd.xml", bts); err != nil {
+		return err
+	}
+	if r.signer == nil {
+		return nil
+	}
+	sig, err := r.signer.detachSign(bts)
+	if err != nil {
+		return err
+	}
+	return r.writeFile("repodata/repomd.xml.asc", sig)
+}
+
+// yumPackage adds the given rpm to the repository and returns its entries
+// for each of the metadata files.
+func (r *repository) yumPackage(rpm *artifact.Artifact) (map[string]yumEntry, error) {
+	info, err := linux.ReadRPM(rpm.Path)
+	if err != nil {
+		return nil, err
+	}
+	location := path.Join("Packages", rpm.Name)
+	if err := r.addPackage(rpm, location); err != nil {
+		return nil, err
+	}
+	hashes, err := hashFile(rpm.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to checksum %s: %w", rpm.Name, err)
+	}
+
+	version := yumVersion{
+		Epoch: fmt.Sprint(info.Epoch),
+		Ver:   info.Version,
+		Rel:   info.Release,
+	}
+	primary := yumPrimaryPackage{
+		Type:        "rpm",
+		Name:        info.Name,
+		Arch:        info.Arch,
+		Version:     version,
+		Checksum:    yumChecksum{Type: "sha256", PkgID: "YES", Value: hashes.SHA256},
+		Summary:     info.Summary,
+		Description: info.Description,
+		Packager:    info.Packager,
+		URL:         info.URL,
+		Time:        yumTime{File: r.ctx.Date.Unix(), Build: int64(info.BuildTime)},
+		Size:        yumSize{Package: hashes.Size, Installed: info.InstalledSize, Archive: info.ArchiveSize},
+		Location:    yumLocation{Href: location},
+		Format: yumFormat{
+			License:     info.License,
+			Vendor:      info.Vendor,
+			Group:       info.Group,
+			BuildHost:   info.BuildHost,
+			SourceRPM:   info.SourceRPM,
+			HeaderRange: yumHeaderRange{Start: info.HeaderStart, End: info.HeaderEnd},
+			Provides:    yumRelations(info.Provides),
+			Requires:    yumRelations(info.Requires),
+			Conflicts:   yumRelations(info.Conflicts),
+			Obsoletes:   yumRelations(info.Obsoletes),
+		},
+	}
+	filelists := yumFilelistsPackage{
+		PkgID:   hashes.SHA256,
+		Name:    info.Name,
+		Arch:    info.Arch,
+		Version: version,
+	}
+	for _, f := range info.Files {
+		file := yumFile{Path: f.Path}
+		switch {
+		case f.Mode.IsDir():
+			file.Type = "dir"
+		case f.Flags&(1<<6) != 0: // RPMFILE_GHOST
+			file.Type = "ghost"
+		}
+		filelists.Files = append(filelists.Files, file)
+		if yumPrimaryFilesRe.MatchString(f.Path) {
+			primary.Format.Files = append(primary.Format.Files, file)
+		}
+	}
+	other := yumOtherPackage{
+		PkgID:   hashes.SHA256,
+		Name:    info.Name,
+		Arch:    info.Arch,
+		Version: version,
+	}
+
+	key := yumKey(info.Name, info.Arch, version)
+	result := map[string]yumEntry{}
+	for kind, v := range map[string]interface{}{
+		"primary":   primary,
+		"filelists": filelists,
+		"other":     other,
+	} {
+		bts, err := xml.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s metadata for %s: %w", kind, rpm.Name, err)
+		}
+		result[kind] = yumEntry{key: key, xml: bts}
+	}
+	return result, nil
+}
+
+// existingYUM returns the package entries of the existing repository by
+// metadata kind.
+func (r *repository) existingYUM() (map[string][]yumEntry, error) {
+	result := map[string][]yumEntry{}
+	bts, err := r.existing.Read(r.ctx, "repodata/repomd.xml")
+	if err != nil || bts == nil {
+		return result, err
+	}
+	var repomd yumRepomd
+	if err := xml.Unmarshal(bts, &repomd); err != nil {
+		return nil, fmt.Errorf("invalid existing repomd.xml: %w", err)
+	}
+	for _, data := range repomd.Data {
+		found := false
+		for _, md := range yumMetadatas {
+			found = found || md.kind == data.Type
+		}
+		if !found {
+			continue
+		}
+		bts, err := r.existing.Read(r.ctx, data.Location.Href)
+		if err != nil {
+			return nil, err
+		}
+		if bts == nil {
+			return nil, fmt.Errorf("existing %s is listed in repomd.xml but does not exist", data.Location.Href)
+		}
+		if strings.HasSuffix(data.Location.Href, ".gz") {
+			gr, err := gzip.NewReader(bytes.NewReader(bts))
+			if err != nil {
+				return nil, fmt.Errorf("invalid existing %s: %w", data.Location.Href, err)
+			}
+			if bts, err = io.ReadAll(gr); err != nil {
+				return nil, fmt.Errorf("invalid existing %s: %w", data.Location.Href, err)
+			}
+		}
+		entries, err := parseYUMEntries(bts)
+		if err != nil {
+			return nil, fmt.Errorf("invalid existing %s: %w", data.Location.Href, err)
+		}
+		result[data.Type] = entries
+	}
+	return result, nil
+}
+
+// parseYUMEntries parses the package entries of a primary, filelists or
+// other metadata file, keeping their raw XML.
+func parseYUMEntries(bts []byte) ([]yumEntry, error) {
+	var result []yumEntry
+	dec := xml.NewDecoder(bytes.NewReader(bts))
+	for {
+		start := dec.InputOffset()
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "package" {
+			continue
+		}
+		var pkg struct {
+			NameAttr string     `xml:"name,attr"`
+			ArchAttr string     `xml:"arch,attr"`
+			Name     string     `xml:"name"`
+			Arch     string     `xml:"arch"`
+			Version  yumVersion `xml:"version"`
+		}
+		if err := dec.DecodeElement(&pkg, &se); err != nil {
+			return nil, err
+		}
+		name, arch := pkg.Name, pkg.Arch
+		if name == "" {
+			name, arch = pkg.NameAttr, pkg.ArchAttr
+		}
+		result = append(result, yumEntry{
+			key: yumKey(name, arch, pkg.Version),
+			xml: append([]byte{}, bts[start:dec.InputOffset()]...),
+		})
+	}
+}
+
+// mergeYUMEntries merges the new entries into the existing ones, replacing
+// packages with the same name, arch and version.
+func mergeYUMEntries(existing, added []yumEntry) []yumEntry {
+	index := map[string]int{}
+	result := make([]yumEntry, 0, len(existing)+len(added))
+	for _, e := range existing {
+		index[e.key] = len(result)
+		result = append(result, e)
+	}
+	for _, e := range added {
+		if i, ok := index[e.key]; ok {
+			result[i] = e
+			continue
+		}
+		index[e.key] = len(result)
+		result = append(result, e)
+	}
+	return result
+}
+
+func yumKey(name, arch string, v yumVersion) string {
+	epoch := v.Epoch
+	if epoch == "" {
+		epoch = "0"
+	}
+	return fmt.Sprintf("%s.%s %s:%s-%s", name, arch, epoch, v.Ver, v.Rel)
+}
+
+func yumRelations(relations []linux.RPMRelation) *yumRelationList {
+	if len(relations) == 0 {
+		return nil
+	}
+	result := &yumRelationList{}
+	for _, rel := range relations {
+		result.Entries = append(result.Entries, yumRelation(rel))
+	}
+	return result
+}
+
+// The rpm namespaced elements use the prefix directly in their names, as
+// encoding/xml can't marshal namespace prefixes.
+
+type yumPrimaryPackage struct {
+	XMLName     xml.Name    `xml:"package"`
+	Type        string      `xml:"type,attr"`
+	Name        string      `xml:"name"`
+	Arch        string      `xml:"arch"`
+	Version     yumVersion  `xml:"version"`
+	Checksum    yumChecksum `xml:"checksum"`
+	Summary     string      `xml:"summary"`
+	Description string      `xml:"description"`
+	Packager    string      `xml:"packager"`
+	URL         string      `xml:"url"`
+	Time        yumTime     `xml:"time"`
+	Size        yumSize     `xml:"size"`
+	Location    yumLocation `xml:"location"`
+	Format      yumFormat   `xml:"format"`
+}
+
+type yumFilelistsPackage struct {
+	XMLName xml.Name   `xml:"package"`
+	PkgID   string     `xml:"pkgid,attr"`
+	Name    string     `xml:"name,attr"`
+	Arch    string     `xml:"arch,attr"`
+	Version yumVersion `xml:"version"`
+	Files   []yumFile  `xml:"file"`
+}
+
+type yumOtherPackage struct {
+	XMLName xml.Name   `xml:"package"`
+	PkgID   string     `xml:"pkgid,attr"`
+	Name    string     `xml:"name,attr"`
+	Arch    string     `xml:"arch,attr"`
+	Version yumVersion `xml:"version"`
+}
+
+type yumVersion struct {
+	Epoch string `xml:"epoch,attr"`
+	Ver   string `xml:"ver,attr"`
+	Rel   string `xml:"rel,attr"`
+}
+
+type yumChecksum struct {
+	Type  string `xml:"type,attr"`
+	PkgID string `xml:"pkgid,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type yumTime struct {
+	File  int64 `xml:"file,attr"`
+	Build int64 `xml:"build,attr"`
+}
+
+type yumSize struct {
+	Package   int64 `xml:"package,attr"`
+	Installed int64 `xml:"installed,attr"`
+	Archive   int64 `xml:"archive,attr"`
+}
+
+type yumLocation struct {
+	Href string `xml:"href,attr"`
+}
+
+type yumFormat struct {
+	License     string           `xml:"rpm:license"`
+	Vendor      string           `xml:"rpm:vendor"`
+	Group       string           `xml:"rpm:group"`
+	BuildHost   string           `xml:"rpm:buildhost"`
+	SourceRPM   string           `xml:"rpm:sourcerpm"`
+	HeaderRange yumHeaderRange   `xml:"rpm:header-range"`
+	Provides    *yumRelationList `xml:"rpm:provides,omitempty"`
+	Requires    *yumRelationList `xml:"rpm:requires,omitempty"`
+	Conflicts   *yumRelationList `xml:"rpm:conflicts,omitempty"`
+	Obsoletes   *yumRelationList `xml:"rpm:obsoletes,omitempty"`
+	Files       []yumFile        `xml:"file"`
+}
+
+type yumHeaderRange struct {
+	Start int64 `xml:"start,attr"`
+	End   int64 `xml:"end,attr"`
+}
+
+type yumRelationList struct {
+	Entries []yumRelation `xml:"rpm:entry"`
+}
+
+type yumRelation struct {
+	Name    string `xml:"name,attr"`
+	Flags   string `xml:"flags,attr,omitempty"`
+	Epoch   string `xml:"epoch,attr,omitempty"`
+	Version string `xml:"ver,attr,omitempty"`
+	Release string `xml:"rel,attr,omitempty"`
+}
+
+type yumFile struct {
+	Type string `xml:"type,attr,omitempty"`
+	Path string `xml:",chardata"`
+}
+
+type yumRepomd struct {
+	XMLName  xml.Name        `xml:"repomd"`
+	Xmlns    string          `xml:"xmlns,attr"`
+	XmlnsRPM string          `xml:"xmlns:rpm,attr"`
+	Revision string          `xml:"revision"`
+	Data     []yumRepomdData `xml:"data"`
+}
+
+type yumRepomdData struct {
+	Type         string      `xml:"type,attr"`
+	Checksum     yumChecksum `xml:"checksum"`
+	OpenChecksum yumChecksum `xml:"open-checksum"`
+	Location     yumLocation `xml:"location"`
+	Timestamp    int64       `xml:"timestamp"`
+	Size         int64       `xml:"size"`
+	OpenSize     int64       `xml:"open-size"`
+}
@@ -0,0 +1,133 @@
+package slack
+
+import (
+	"fmt"
+
+	"github.com/apex/log"
+	"github.com/caarlos0/env/v6"
+	"github.com/goreleaser/goreleaser/internal/announcer"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+type Pipe struct{}
+
+func (Pipe) String() string { return "slack" }
+
+type Config struct {
+	Webhook string `env:"SLACK_WEBHOOK,notEmpty"`
+}
+
+func (Pipe) Default(ctx *context.Context) error {
+	if ctx.Config.Announce.Slack.MessageTemplate == "" {
+		ctx.Config.Announce.Slack.MessageTemplate = announcer.DefaultMessageTemplate
+	}
+	return nil
+}
+
+// message is the payload of a Slack incoming webhook.
+type message struct {
+	Text      string        `json:"text"`
+	Channel   string        `json:"channel,omitempty"`
+	Username  string        `json:"username,omitempty"`
+	IconEmoji string        `json:"icon_emoji,omitempty"`
+	IconURL   string        `json:"icon_url,omitempty"`
+	Blocks    []interface{} `json:"blocks,omitempty"`
+}
+
+func (Pipe) Announce(ctx *context.Context) error {
+	if ctx.SkipAnnounce {
+		return pipe.ErrSkipAnnounceEnabled
+	}
+	if !ctx.Config.Announce.Slack.Enabled {
+		return pipe.ErrSkipDisabledPipe
+	}
+
+	msg, err := newMessage(ctx)
+	if err != nil {
+		return fmt.Errorf("announce: failed to announce to slack: %w", err)
+	}
+
+	var cfg Config
+	if err := env.Parse(&cfg); err != nil {
+		return fmt.Errorf("announce: failed to announce to slack: %w", err)
+	}
+
+	log.Infof("posting: '%s'", msg.Text)
+	if err := announcer.PostJSON(ctx, cfg.Webhook, msg, nil); err != nil {
+		return fmt.Errorf("announce: failed to announce to slack: %w", err)
+	}
+	return nil
+}
+
+func newMessage(ctx *context.Context) (message, error) {
+	conf := ctx.Config.Announce.Slack
+	t := tmpl.New(ctx)
+	var msg message
+	for _, field := range []struct {
+		tmpl  string
+		value *string
+	}{
+		{conf.MessageTemplate, &msg.Text},
+		{conf.Channel, &msg.Channel},
+		{conf.Username, &msg.Username},
+		{conf.IconEmoji, &msg.IconEmoji},
+		{conf.IconURL, &msg.IconURL},
+	} {
+		value, err := t.Apply(field.tmpl)
+		if err != nil {
+			return msg, err
+		}
+		*field.value = value
+	}
+	for _, block := range conf.Blocks {
+		block, err := applyBlock(t, block)
+		if err != nil {
+			return msg, err
+		}
+		msg.Blocks = append(msg.Blocks, block)
+	}
+	return msg, nil
+}
+
+// applyBlock applies the template to all the strings of the given block, also
+// converting the maps decoded from YAML so they can be encoded to JSON.
+func applyBlock(t *tmpl.Template, block interface{}) (interface{}, error) {
+	switch v := block.(type) {
+	case string:
+		return t.Apply(v)
+	case map[interface{}]interface{}:
+		result := map[string]interface{}{}
+		for k, value := range v {
+			value, err := applyBlock(t, value)
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprint(k)] = value
+		}
+		return result, nil
+	case map[string]interface{}:
+		result := map[string]interface{}{}
+		for k, value := range v {
+			value, err := applyBlock(t, value)
+			if err != nil {
+				return nil, err
+			}
+			result[k] = value
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			value, err := applyBlock(t, value)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		return result, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,166 @@
+package slack
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/announcer"
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestStringer(t *testing.T) {
+	require.Equal(t, Pipe{}.String(), "slack")
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, ctx.Config.Announce.Slack.MessageTemplate, announcer.DefaultMessageTemplate)
+}
+
+func TestAnnounceDisabled(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceSkipAnnounce(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Slack: config.Slack{
+				Enabled: true,
+			},
+		},
+	})
+	ctx.SkipAnnounce = true
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceInvalidTemplate(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Slack: config.Slack{
+				Enabled:         true,
+				MessageTemplate: "{{ .Foo }",
+			},
+		},
+	})
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to slack: template: tmpl:1: unexpected "}" in operand`)
+}
+
+func TestAnnounceInvalidBlockTemplate(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Slack: config.Slack{
+				Enabled:         true,
+				MessageTemplate: "foo",
+				Blocks: []interface{}{
+					map[interface{}]interface{}{"text": "{{ .Foo }"},
+				},
+			},
+		},
+	})
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to slack: template: tmpl:1: unexpected "}" in operand`)
+}
+
+func TestAnnounceMissingEnv(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Slack: config.Slack{
+				Enabled: true,
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to slack: env: environment variable "SLACK_WEBHOOK" should not be empty`)
+}
+
+func TestAnnounce(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+	setWebhook(t, srv.URL)
+
+	var blocks []interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+- type: section
+  text:
+    type: mrkdwn
+    text: "*{{ .ProjectName }}* {{ .Tag }}"
+- type: divider
+`), &blocks))
+
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Announce: config.Announce{
+			Slack: config.Slack{
+				Enabled:   true,
+				Channel:   "#releases",
+				Username:  "{{ .ProjectName }} bot",
+				IconEmoji: ":rocket:",
+				Blocks:    blocks,
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Git.URL = "https://github.com/foo/bar"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+	require.Equal(t, map[string]interface{}{
+		"text":       "foo v1.0.0 is out! Check it out at https://github.com/foo/bar/releases/tag/v1.0.0",
+		"channel":    "#releases",
+		"username":   "foo bot",
+		"icon_emoji": ":rocket:",
+		"blocks": []interface{}{
+			map[string]interface{}{
+				"type": "section",
+				"text": map[string]interface{}{
+					"type": "mrkdwn",
+					"text": "*foo* v1.0.0",
+				},
+			},
+			map[string]interface{}{
+				"type": "divider",
+			},
+		},
+	}, body)
+}
+
+func TestAnnounceError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, "no_service")
+	}))
+	defer srv.Close()
+	setWebhook(t, srv.URL)
+
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Slack: config.Slack{
+				Enabled: true,
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to slack: unexpected http status code 404: no_service`)
+}
+
+func setWebhook(t *testing.T, url string) {
+	t.Helper()
+	require.NoError(t, os.Setenv("SLACK_WEBHOOK", url))
+	t.Cleanup(func() {
+		require.NoError(t, os.Unsetenv("SLACK_WEBHOOK"))
+	})
+}
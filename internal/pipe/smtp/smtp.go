@@ -0,0 +1,276 @@
+package smtp
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	gosmtp "net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apex/log"
+	"github.com/caarlos0/env/v6"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const (
+	// TLSStartTLS upgrades the connection with the STARTTLS command.
+	TLSStartTLS = "starttls"
+	// TLSImplicit connects to the server using TLS right away.
+	TLSImplicit = "tls"
+	// TLSNone sends the email without encryption.
+	TLSNone = "none"
+
+	// BodyFormatText sends the body as plain text.
+	BodyFormatText = "text"
+	// BodyFormatHTML sends the body as HTML.
+	BodyFormatHTML = "html"
+
+	defaultPort            = 587
+	defaultSubjectTemplate = `{{ .ProjectName }} {{ .Tag }} is out!`
+	defaultBodyTemplate    = `You can view details from: {{ .ReleaseURL }}
+
+{{ .ReleaseNotes }}`
+)
+
+type Pipe struct{}
+
+func (Pipe) String() string { return "smtp" }
+
+type Config struct {
+	Password string `env:"SMTP_PASSWORD"`
+}
+
+func (Pipe) Default(ctx *context.Context) error {
+	if ctx.Config.Announce.SMTP.Port == 0 {
+		ctx.Config.Announce.SMTP.Port = defaultPort
+	}
+	if ctx.Config.Announce.SMTP.TLS == "" {
+		ctx.Config.Announce.SMTP.TLS = TLSStartTLS
+	}
+	if ctx.Config.Announce.SMTP.SubjectTemplate == "" {
+		ctx.Config.Announce.SMTP.SubjectTemplate = defaultSubjectTemplate
+	}
+	if ctx.Config.Announce.SMTP.BodyTemplate == "" {
+		ctx.Config.Announce.SMTP.BodyTemplate = defaultBodyTemplate
+	}
+	if ctx.Config.Announce.SMTP.BodyFormat == "" {
+		ctx.Config.Announce.SMTP.BodyFormat = BodyFormatText
+	}
+	return nil
+}
+
+func (Pipe) Announce(ctx *context.Context) error {
+	if ctx.SkipAnnounce {
+		return pipe.ErrSkipAnnounceEnabled
+	}
+	if !ctx.Config.Announce.SMTP.Enabled {
+		return pipe.ErrSkipDisabledPipe
+	}
+
+	if err := checkConfig(ctx); err != nil {
+		return fmt.Errorf("announce: failed to announce to smtp: %w", err)
+	}
+
+	subject, body, err := newMessage(ctx)
+	if err != nil {
+		return fmt.Errorf("announce: failed to announce to smtp: %w", err)
+	}
+
+	var cfg Config
+	if err := env.Parse(&cfg); err != nil {
+		return fmt.Errorf("announce: failed to announce to smtp: %w", err)
+	}
+
+	log.Infof("sending: '%s'", subject)
+	if err := send(ctx, cfg.Password, subject, body); err != nil {
+		return fmt.Errorf("announce: failed to announce to smtp: %w", err)
+	}
+	return nil
+}
+
+func checkConfig(ctx *context.Context) error {
+	conf := ctx.Config.Announce.SMTP
+	if conf.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	if conf.From == "" {
+		return fmt.Errorf("from is required")
+	}
+	if len(conf.To) == 0 {
+		return fmt.Errorf("at least one recipient is required")
+	}
+	switch conf.TLS {
+	case TLSStartTLS, TLSImplicit, TLSNone:
+	default:
+		return fmt.Errorf("invalid tls mode: %s", conf.TLS)
+	}
+	switch conf.BodyFormat {
+	case BodyFormatText, BodyFormatHTML:
+	default:
+		return fmt.Errorf("invalid body_format: %s", conf.BodyFormat)
+	}
+	return nil
+}
+
+func newMessage(ctx *context.Context) (string, string, error) {
+	conf := ctx.Config.Announce.SMTP
+	t := tmpl.New(ctx).WithExtraFields(tmpl.Fields{
+		"ReleaseNotes": ctx.ReleaseNotes,
+		"ReleaseURL":   releaseURL(ctx),
+	})
+	subject, err := t.Apply(conf.SubjectTemplate)
+	if err != nil {
+		return "", "", err
+	}
+	body, err := t.Apply(conf.BodyTemplate)
+	if err != nil {
+		return "", "", err
+	}
+	return subject, body, nil
+}
+
+// releaseURL returns the URL of the release page in the configured SCM.
+func releaseURL(ctx *context.Context) string {
+	tag := ctx.Git.CurrentTag
+	switch ctx.TokenType {
+	case context.TokenTypeGitLab:
+		return fmt.Sprintf(
+			"%s/%s/%s/-/releases/%s",
+			ctx.Config.GitLabURLs.Download,
+			ctx.Config.Release.GitLab.Owner,
+			ctx.Config.Release.GitLab.Name,
+			tag,
+		)
+	case context.TokenTypeGitea:
+		return fmt.Sprintf(
+			"%s/%s/%s/releases/tag/%s",
+			ctx.Config.GiteaURLs.Download,
+			ctx.Config.Release.Gitea.Owner,
+			ctx.Config.Release.Gitea.Name,
+			tag,
+		)
+	default:
+		return fmt.Sprintf(
+			"%s/%s/%s/releases/tag/%s",
+			ctx.Config.GitHubURLs.Download,
+			ctx.Config.Release.GitHub.Owner,
+			ctx.Config.Release.GitHub.Name,
+			tag,
+		)
+	}
+}
+
+// compose builds the RFC 5322 message with the given subject and body.
+func compose(ctx *context.Context, subject, body string) ([]byte, error) {
+	conf := ctx.Config.Announce.SMTP
+	contentType := "text/plain"
+	if conf.BodyFormat == BodyFormatHTML {
+		contentType = "text/html"
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", conf.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(conf.To, ", "))
+	if len(conf.CC) > 0 {
+		fmt.Fprintf(&msg, "Cc: %s\r\n", strings.Join(conf.CC, ", "))
+	}
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: %s; charset=UTF-8\r\n", contentType)
+	fmt.Fprintf(&msg, "Content-Transfer-Encoding: quoted-printable\r\n")
+	fmt.Fprintf(&msg, "\r\n")
+
+	w := quotedprintable.NewWriter(&msg)
+	if _, err := w.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return msg.Bytes(), nil
+}
+
+func send(ctx *context.Context, password, subject, body string) error {
+	conf := ctx.Config.Announce.SMTP
+	msg, err := compose(ctx, subject, body)
+	if err != nil {
+		return err
+	}
+
+	c, err := dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if conf.Username != "" {
+		auth := gosmtp.PlainAuth("", conf.Username, password, conf.Host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	if err := c.Mail(conf.From); err != nil {
+		return err
+	}
+	for _, rcpt := range append(append([]string{}, conf.To...), conf.CC...) {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("failed to add recipient %s: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// dial connects to the configured server, setting up TLS as configured.
+func dial(ctx *context.Context) (*gosmtp.Client, error) {
+	conf := ctx.Config.Announce.SMTP
+	addr := net.JoinHostPort(conf.Host, strconv.Itoa(conf.Port))
+	tlsConfig := &tls.Config{
+		ServerName:         conf.Host,
+		InsecureSkipVerify: conf.InsecureSkipVerify, // nolint: gosec
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if conf.TLS == TLSImplicit {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	c, err := gosmtp.NewClient(conn, conf.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if conf.TLS == TLSStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			c.Close()
+			return nil, fmt.Errorf("server does not support STARTTLS")
+		}
+		if err := c.StartTLS(tlsConfig); err != nil {
+			c.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
@@ -0,0 +1,324 @@
+package smtp
+
+import (
+	"encoding/base64"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"net/textproto"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+// server is a minimal SMTP server, recording the received emails.
+type server struct {
+	lock     sync.Mutex
+	auth     string
+	from     string
+	rcpts    []string
+	data     string
+	rejectTo string
+}
+
+func (s *server) serve(t *testing.T, l net.Listener) {
+	t.Helper()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *server) handle(conn net.Conn) {
+	defer conn.Close()
+	c := textproto.NewConn(conn)
+	_ = c.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		arg := strings.TrimSpace(strings.TrimPrefix(line, strings.SplitN(line, " ", 2)[0]))
+		s.lock.Lock()
+		switch cmd {
+		case "EHLO":
+			_ = c.PrintfLine("250-localhost")
+			_ = c.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			s.auth = strings.TrimPrefix(arg, "PLAIN ")
+			_ = c.PrintfLine("235 2.7.0 Authentication successful")
+		case "MAIL":
+			s.from = arg
+			_ = c.PrintfLine("250 OK")
+		case "RCPT":
+			if s.rejectTo != "" && strings.Contains(arg, s.rejectTo) {
+				_ = c.PrintfLine("550 no such user")
+				break
+			}
+			s.rcpts = append(s.rcpts, arg)
+			_ = c.PrintfLine("250 OK")
+		case "DATA":
+			_ = c.PrintfLine("354 go ahead")
+			bts, _ := io.ReadAll(c.DotReader())
+			s.data = string(bts)
+			_ = c.PrintfLine("250 OK")
+		case "QUIT":
+			_ = c.PrintfLine("221 bye")
+			s.lock.Unlock()
+			return
+		default:
+			_ = c.PrintfLine("502 not implemented")
+		}
+		s.lock.Unlock()
+	}
+}
+
+func newServer(t *testing.T) (*server, int) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	s := &server{}
+	go s.serve(t, l)
+	return s, l.Addr().(*net.TCPAddr).Port
+}
+
+func setPassword(t *testing.T, password string) {
+	t.Helper()
+	require.NoError(t, os.Setenv("SMTP_PASSWORD", password))
+	t.Cleanup(func() {
+		require.NoError(t, os.Unsetenv("SMTP_PASSWORD"))
+	})
+}
+
+func TestStringer(t *testing.T) {
+	require.Equal(t, Pipe{}.String(), "smtp")
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, config.SMTP{
+		Port:            defaultPort,
+		TLS:             TLSStartTLS,
+		SubjectTemplate: defaultSubjectTemplate,
+		BodyTemplate:    defaultBodyTemplate,
+		BodyFormat:      BodyFormatText,
+	}, ctx.Config.Announce.SMTP)
+}
+
+func TestAnnounceDisabled(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceSkipAnnounce(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			SMTP: config.SMTP{
+				Enabled: true,
+			},
+		},
+	})
+	ctx.SkipAnnounce = true
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceInvalidConfig(t *testing.T) {
+	for expected, conf := range map[string]config.SMTP{
+		"host is required":                   {},
+		"from is required":                   {Host: "localhost"},
+		"at least one recipient is required": {Host: "localhost", From: "a@example.com"},
+		"invalid tls mode: ssl":              {Host: "localhost", From: "a@example.com", To: []string{"b@example.com"}, TLS: "ssl"},
+		"invalid body_format: markdown":      {Host: "localhost", From: "a@example.com", To: []string{"b@example.com"}, BodyFormat: "markdown"},
+	} {
+		t.Run(expected, func(t *testing.T) {
+			conf.Enabled = true
+			ctx := context.New(config.Project{
+				Announce: config.Announce{
+					SMTP: conf,
+				},
+			})
+			require.NoError(t, Pipe{}.Default(ctx))
+			require.EqualError(t, Pipe{}.Announce(ctx), "announce: failed to announce to smtp: "+expected)
+		})
+	}
+}
+
+func TestAnnounceInvalidTemplate(t *testing.T) {
+	for name, conf := range map[string]config.SMTP{
+		"subject": {SubjectTemplate: "{{ .Foo }"},
+		"body":    {BodyTemplate: "{{ .Foo }"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf.Enabled = true
+			conf.Host = "localhost"
+			conf.From = "a@example.com"
+			conf.To = []string{"b@example.com"}
+			ctx := context.New(config.Project{
+				Announce: config.Announce{
+					SMTP: conf,
+				},
+			})
+			require.NoError(t, Pipe{}.Default(ctx))
+			require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to smtp: template: tmpl:1: unexpected "}" in operand`)
+		})
+	}
+}
+
+func TestAnnounce(t *testing.T) {
+	srv, port := newServer(t)
+	setPassword(t, "secret")
+
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Release: config.Release{
+			GitHub: config.Repo{Owner: "goreleaser", Name: "foo"},
+		},
+		GitHubURLs: config.GitHubURLs{Download: "https://github.com"},
+		Announce: config.Announce{
+			SMTP: config.SMTP{
+				Enabled:  true,
+				Host:     "127.0.0.1",
+				Port:     port,
+				TLS:      TLSNone,
+				Username: "bot",
+				From:     "releases@example.com",
+				To:       []string{"list@example.com"},
+				CC:       []string{"team@example.com"},
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.ReleaseNotes = "## Changelog\n\n* fixed everything"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	auth, err := base64.StdEncoding.DecodeString(srv.auth)
+	require.NoError(t, err)
+	require.Equal(t, "\x00bot\x00secret", string(auth))
+	require.Equal(t, "FROM:<releases@example.com>", srv.from)
+	require.Equal(t, []string{"TO:<list@example.com>", "TO:<team@example.com>"}, srv.rcpts)
+
+	headers, body := splitMessage(t, srv.data)
+	require.Contains(t, headers, "From: releases@example.com")
+	require.Contains(t, headers, "To: list@example.com")
+	require.Contains(t, headers, "Cc: team@example.com")
+	require.Contains(t, headers, "Subject: foo v1.0.0 is out!")
+	require.Contains(t, headers, "Content-Type: text/plain; charset=UTF-8")
+	require.Equal(t, "You can view details from: https://github.com/goreleaser/foo/releases/tag/v1.0.0\n\n## Changelog\n\n* fixed everything", body)
+}
+
+func TestAnnounceHTML(t *testing.T) {
+	srv, port := newServer(t)
+
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Announce: config.Announce{
+			SMTP: config.SMTP{
+				Enabled:         true,
+				Host:            "127.0.0.1",
+				Port:            port,
+				TLS:             TLSNone,
+				From:            "releases@example.com",
+				To:              []string{"list@example.com"},
+				SubjectTemplate: "{{ .ProjectName }} {{ .Tag }} está disponível",
+				BodyTemplate:    "<h1>{{ .ProjectName }} {{ .Tag }}</h1>",
+				BodyFormat:      BodyFormatHTML,
+			},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+
+	srv.lock.Lock()
+	defer srv.lock.Unlock()
+	require.Empty(t, srv.auth)
+	headers, body := splitMessage(t, srv.data)
+	require.Contains(t, headers, "Subject: =?utf-8?q?foo_v1.0.0_est=C3=A1_dispon=C3=ADvel?=")
+	require.Contains(t, headers, "Content-Type: text/html; charset=UTF-8")
+	require.Equal(t, "<h1>foo v1.0.0</h1>", body)
+}
+
+func TestAnnounceRejectedRecipient(t *testing.T) {
+	srv, port := newServer(t)
+	srv.rejectTo = "nope@example.com"
+
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			SMTP: config.SMTP{
+				Enabled: true,
+				Host:    "127.0.0.1",
+				Port:    port,
+				TLS:     TLSNone,
+				From:    "releases@example.com",
+				To:      []string{"nope@example.com"},
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), "announce: failed to announce to smtp: failed to add recipient nope@example.com: 550 \"no such user\"")
+}
+
+func TestAnnounceStartTLSNotSupported(t *testing.T) {
+	_, port := newServer(t)
+
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			SMTP: config.SMTP{
+				Enabled: true,
+				Host:    "127.0.0.1",
+				Port:    port,
+				From:    "releases@example.com",
+				To:      []string{"list@example.com"},
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), "announce: failed to announce to smtp: server does not support STARTTLS")
+}
+
+func TestReleaseURL(t *testing.T) {
+	ctx := context.New(config.Project{
+		Release: config.Release{
+			GitHub: config.Repo{Owner: "a", Name: "b"},
+			GitLab: config.Repo{Owner: "c", Name: "d"},
+			Gitea:  config.Repo{Owner: "e", Name: "f"},
+		},
+		GitHubURLs: config.GitHubURLs{Download: "https://github.com"},
+		GitLabURLs: config.GitLabURLs{Download: "https://gitlab.com"},
+		GiteaURLs:  config.GiteaURLs{Download: "https://gitea.com"},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+
+	ctx.TokenType = context.TokenTypeGitHub
+	require.Equal(t, "https://github.com/a/b/releases/tag/v1.0.0", releaseURL(ctx))
+	ctx.TokenType = context.TokenTypeGitLab
+	require.Equal(t, "https://gitlab.com/c/d/-/releases/v1.0.0", releaseURL(ctx))
+	ctx.TokenType = context.TokenTypeGitea
+	require.Equal(t, "https://gitea.com/e/f/releases/tag/v1.0.0", releaseURL(ctx))
+}
+
+func splitMessage(t *testing.T, data string) ([]string, string) {
+	t.Helper()
+	parts := strings.SplitN(data, "\n\n", 2)
+	require.Len(t, parts, 2)
+	body, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(parts[1])))
+	require.NoError(t, err)
+	// the data writer always ends the message with a new line
+	return strings.Split(parts[0], "\n"), strings.TrimSuffix(string(body), "\n")
+}
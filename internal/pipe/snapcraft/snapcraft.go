@@ -63,6 +63,10 @@ type LayoutMetadata struct {
 	Type     string `yaml:",omitempty"`
 }
 
+// channelsExtra is the artifact extra holding the channels a snap is
+// released to.
+const channelsExtra = "Channels"
+
 const defaultNameTemplate = "{{ .ProjectName }}_{{ .Version }}_{{ .Os }}_{{ .Arch }}{{ if .Arm }}v{{ .Arm }}{{ end }}{{ if .Mips }}_{{ .Mips }}{{ end }}"
 
 // Pipe for snapcraft packaging.
@@ -85,6 +89,9 @@ func (Pipe) Default(ctx *context.Context) error {
 				snap.Builds = append(snap.Builds, b.ID)
 			}
 		}
+		if len(snap.ChannelTemplates) == 0 {
+			snap.ChannelTemplates = []string{"stable"}
+		}
 		ids.Inc(snap.ID)
 	}
 	return ids.Validate()
@@ -319,6 +326,10 @@ func create(ctx *context.Context, snap config.Snapcraft, arch string, binaries [
 	if !snap.Publish {
 		return nil
 	}
+	channels, err := channels(ctx, snap, binaries[0])
+	if err != nil {
+		return err
+	}
 	ctx.Artifacts.Add(&artifact.Artifact{
 		Type:   artifact.PublishableSnapcraft,
 		Name:   folder + ".snap",
@@ -326,10 +337,32 @@ func create(ctx *context.Context, snap config.Snapcraft, arch string, binaries [
 		Goos:   binaries[0].Goos,
 		Goarch: binaries[0].Goarch,
 		Goarm:  binaries[0].Goarm,
+		Extra: map[string]interface{}{
+			channelsExtra: channels,
+		},
 	})
 	return nil
 }
 
+// channels renders the channel templates of the given snap, ignoring the
+// ones that evaluate to an empty string.
+func channels(ctx *context.Context, snap config.Snapcraft, binary *artifact.Artifact) ([]string, error) {
+	tmpl := tmpl.New(ctx).WithArtifact(binary, snap.Replacements)
+	var result []string
+	for _, template := range snap.ChannelTemplates {
+		channel, err := tmpl.Apply(template)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute channel template '%s': %w", template, err)
+		}
+		channel = strings.TrimSpace(channel)
+		if channel == "" {
+			continue
+		}
+		result = append(result, channel)
+	}
+	return result, nil
+}
+
 const (
 	reviewWaitMsg  = `Waiting for previous upload(s) to complete their review process.`
 	humanReviewMsg = `A human will soon review your snap`
@@ -337,11 +370,16 @@ const (
 )
 
 func push(ctx *context.Context, snap *artifact.Artifact) error {
-	log := log.WithField("snap", snap.Name)
+	channels := snap.ExtraOr(channelsExtra, []string{}).([]string)
+	log := log.WithField("snap", snap.Name).WithField("channels", channels)
 	log.Info("pushing snap")
-	// TODO: customize --release based on snap.Grade?
+	args := []string{"upload"}
+	if len(channels) > 0 {
+		args = append(args, "--release="+strings.Join(channels, ","))
+	}
+	args = append(args, snap.Path)
 	/* #nosec */
-	cmd := exec.CommandContext(ctx, "snapcraft", "upload", "--release=stable", snap.Path)
+	cmd := exec.CommandContext(ctx, "snapcraft", args...)
 	if out, err := cmd.CombinedOutput(); err != nil {
 		if strings.Contains(string(out), reviewWaitMsg) || strings.Contains(string(out), humanReviewMsg) || strings.Contains(string(out), needsReviewMsg) {
 			log.Warn(reviewWaitMsg)
@@ -375,6 +375,49 @@ func TestDefault(t *testing.T) {
 	require.NoError(t, Pipe{}.Default(ctx))
 	require.Equal(t, defaultNameTemplate, ctx.Config.Snapcrafts[0].NameTemplate)
 	require.Equal(t, []string{"foo"}, ctx.Config.Snapcrafts[0].Builds)
+	require.Equal(t, []string{"stable"}, ctx.Config.Snapcrafts[0].ChannelTemplates)
+}
+
+func TestDefaultChannelTemplates(t *testing.T) {
+	ctx := context.New(config.Project{
+		Snapcrafts: []config.Snapcraft{
+			{ID: "a", Grade: "devel"},
+			{ID: "b", Grade: "devel", ChannelTemplates: []string{"edge"}},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, []string{"stable"}, ctx.Config.Snapcrafts[0].ChannelTemplates)
+	require.Equal(t, []string{"edge"}, ctx.Config.Snapcrafts[1].ChannelTemplates)
+}
+
+func TestChannels(t *testing.T) {
+	snap := config.Snapcraft{
+		ChannelTemplates: []string{
+			"edge",
+			"{{ if not .Prerelease }}stable{{ end }}",
+			"{{ .Major }}.{{ .Minor }}/{{ if .Prerelease }}beta{{ else }}stable{{ end }}",
+		},
+	}
+	binary := &artifact.Artifact{Goos: "linux", Goarch: "amd64"}
+
+	ctx := context.New(config.Project{})
+	ctx.Semver = context.Semver{Major: 1, Minor: 2, Patch: 3}
+	result, err := channels(ctx, snap, binary)
+	require.NoError(t, err)
+	require.Equal(t, []string{"edge", "stable", "1.2/stable"}, result)
+
+	ctx.Semver.Prerelease = "rc1"
+	result, err = channels(ctx, snap, binary)
+	require.NoError(t, err)
+	require.Equal(t, []string{"edge", "1.2/beta"}, result)
+}
+
+func TestChannelsInvalidTemplate(t *testing.T) {
+	_, err := channels(context.New(config.Project{}), config.Snapcraft{
+		ChannelTemplates: []string{"{{ .Nope }"},
+	}, &artifact.Artifact{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "failed to execute channel template '{{ .Nope }'")
 }
 
 func TestPublish(t *testing.T) {
@@ -0,0 +1,81 @@
+package telegram
+
+import (
+	"fmt"
+
+	"github.com/apex/log"
+	"github.com/caarlos0/env/v6"
+	"github.com/goreleaser/goreleaser/internal/announcer"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const defaultMessageTemplate = `{{ mdv2escape .ProjectName }} {{ mdv2escape .Tag }} is out! Check it out at {{ mdv2escape .GitURL }}/releases/tag/{{ mdv2escape .Tag }}`
+
+// nolint: gochecknoglobals
+var apiURL = "https://api.telegram.org"
+
+type Pipe struct{}
+
+func (Pipe) String() string { return "telegram" }
+
+type Config struct {
+	Token string `env:"TELEGRAM_TOKEN,notEmpty"`
+}
+
+func (Pipe) Default(ctx *context.Context) error {
+	if ctx.Config.Announce.Telegram.MessageTemplate == "" {
+		ctx.Config.Announce.Telegram.MessageTemplate = defaultMessageTemplate
+	}
+	return nil
+}
+
+// message is the payload of the sendMessage method of the bot API.
+type message struct {
+	ChatID    string `json:"chat_id"`
+	Text      string `json:"text"`
+	ParseMode string `json:"parse_mode"`
+}
+
+func (Pipe) Announce(ctx *context.Context) error {
+	if ctx.SkipAnnounce {
+		return pipe.ErrSkipAnnounceEnabled
+	}
+	if !ctx.Config.Announce.Telegram.Enabled {
+		return pipe.ErrSkipDisabledPipe
+	}
+
+	t := tmpl.New(ctx)
+	chatID, err := t.Apply(ctx.Config.Announce.Telegram.ChatID)
+	if err != nil {
+		return fmt.Errorf("announce: failed to announce to telegram: %w", err)
+	}
+	if chatID == "" {
+		return fmt.Errorf("announce: failed to announce to telegram: chat_id is required")
+	}
+	text, err := t.Apply(ctx.Config.Announce.Telegram.MessageTemplate)
+	if err != nil {
+		return fmt.Errorf("announce: failed to announce to telegram: %w", err)
+	}
+
+	var cfg Config
+	if err := env.Parse(&cfg); err != nil {
+		return fmt.Errorf("announce: failed to announce to telegram: %w", err)
+	}
+
+	log.Infof("posting: '%s'", text)
+	if err := post(ctx, cfg.Token, message{
+		ChatID:    chatID,
+		Text:      text,
+		ParseMode: "MarkdownV2",
+	}); err != nil {
+		return fmt.Errorf("announce: failed to announce to telegram: %w", err)
+	}
+	return nil
+}
+
+func post(ctx *context.Context, token string, msg message) error {
+	endpoint := fmt.Sprintf("%s/bot%s/sendMessage", apiURL, token)
+	return announcer.PostJSON(ctx, endpoint, msg, nil)
+}
@@ -0,0 +1,170 @@
+package telegram
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringer(t *testing.T) {
+	require.Equal(t, Pipe{}.String(), "telegram")
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, ctx.Config.Announce.Telegram.MessageTemplate, defaultMessageTemplate)
+}
+
+func TestAnnounceDisabled(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceSkipAnnounce(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Telegram: config.Telegram{
+				Enabled: true,
+			},
+		},
+	})
+	ctx.SkipAnnounce = true
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceMissingChatID(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Telegram: config.Telegram{
+				Enabled: true,
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to telegram: chat_id is required`)
+}
+
+func TestAnnounceInvalidTemplate(t *testing.T) {
+	for name, conf := range map[string]config.Telegram{
+		"chat_id": {ChatID: "{{ .Foo }"},
+		"message": {ChatID: "123", MessageTemplate: "{{ .Foo }"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			conf.Enabled = true
+			ctx := context.New(config.Project{
+				Announce: config.Announce{
+					Telegram: conf,
+				},
+			})
+			require.NoError(t, Pipe{}.Default(ctx))
+			require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to telegram: template: tmpl:1: unexpected "}" in operand`)
+		})
+	}
+}
+
+func TestAnnounceMissingEnv(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Telegram: config.Telegram{
+				Enabled: true,
+				ChatID:  "123",
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to telegram: env: environment variable "TELEGRAM_TOKEN" should not be empty`)
+}
+
+func TestAnnounce(t *testing.T) {
+	var path string
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		_, _ = io.WriteString(w, `{"ok":true}`)
+	}))
+	defer srv.Close()
+	setAPI(t, srv.URL)
+
+	ctx := context.New(config.Project{
+		ProjectName: "foo_bar",
+		Announce: config.Announce{
+			Telegram: config.Telegram{
+				Enabled: true,
+				ChatID:  "{{ .Env.CHAT_ID }}",
+			},
+		},
+	})
+	ctx.Env["CHAT_ID"] = "-100123"
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Git.URL = "https://github.com/foo/bar"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+	require.Equal(t, "/bottoken/sendMessage", path)
+	require.Equal(t, map[string]interface{}{
+		"chat_id":    "-100123",
+		"text":       `foo\_bar v1\.0\.0 is out! Check it out at https://github\.com/foo/bar/releases/tag/v1\.0\.0`,
+		"parse_mode": "MarkdownV2",
+	}, body)
+}
+
+func TestAnnounceError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, `{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+	}))
+	defer srv.Close()
+	setAPI(t, srv.URL)
+
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Telegram: config.Telegram{
+				Enabled: true,
+				ChatID:  "123",
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to telegram: unexpected http status code 400: {"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`)
+}
+
+func TestAnnounceErrorHidesToken(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close()
+	setAPI(t, srv.URL)
+
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Telegram: config.Telegram{
+				Enabled: true,
+				ChatID:  "123",
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	err := Pipe{}.Announce(ctx)
+	require.Error(t, err)
+	require.False(t, strings.Contains(err.Error(), "token"), err.Error())
+}
+
+func setAPI(t *testing.T, url string) {
+	t.Helper()
+	previous := apiURL
+	apiURL = url
+	require.NoError(t, os.Setenv("TELEGRAM_TOKEN", "token"))
+	t.Cleanup(func() {
+		apiURL = previous
+		require.NoError(t, os.Unsetenv("TELEGRAM_TOKEN"))
+	})
+}
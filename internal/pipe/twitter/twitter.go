@@ -7,13 +7,12 @@ import (
 	"github.com/caarlos0/env/v6"
 	"github.com/dghubble/go-twitter/twitter"
 	"github.com/dghubble/oauth1"
+	"github.com/goreleaser/goreleaser/internal/announcer"
 	"github.com/goreleaser/goreleaser/internal/pipe"
 	"github.com/goreleaser/goreleaser/internal/tmpl"
 	"github.com/goreleaser/goreleaser/pkg/context"
 )
 
-const defaultMessageTemplate = `{{ .ProjectName }} {{ .Tag }} is out! Check it out at {{ .GitURL }}/releases/tag/{{ .Tag }}`
-
 type Pipe struct{}
 
 func (Pipe) String() string { return "twitter" }
@@ -27,7 +26,7 @@ type Config struct {
 
 func (Pipe) Default(ctx *context.Context) error {
 	if ctx.Config.Announce.Twitter.MessageTemplate == "" {
-		ctx.Config.Announce.Twitter.MessageTemplate = defaultMessageTemplate
+		ctx.Config.Announce.Twitter.MessageTemplate = announcer.DefaultMessageTemplate
 	}
 	return nil
 }
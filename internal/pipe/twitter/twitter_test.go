@@ -3,6 +3,7 @@ package twitter
 import (
 	"testing"
 
+	"github.com/goreleaser/goreleaser/internal/announcer"
 	"github.com/goreleaser/goreleaser/internal/testlib"
 	"github.com/goreleaser/goreleaser/pkg/config"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -16,7 +17,7 @@ func TestStringer(t *testing.T) {
 func TestDefault(t *testing.T) {
 	ctx := context.New(config.Project{})
 	require.NoError(t, Pipe{}.Default(ctx))
-	require.Equal(t, ctx.Config.Announce.Twitter.MessageTemplate, defaultMessageTemplate)
+	require.Equal(t, ctx.Config.Announce.Twitter.MessageTemplate, announcer.DefaultMessageTemplate)
 }
 
 func TestAnnounceDisabled(t *testing.T) {
@@ -0,0 +1,88 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/apex/log"
+	"github.com/goreleaser/goreleaser/internal/announcer"
+	"github.com/goreleaser/goreleaser/internal/pipe"
+	"github.com/goreleaser/goreleaser/internal/tmpl"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+)
+
+const defaultContentType = "application/json; charset=utf-8"
+
+// DefaultMessageTemplate is the body template used by webhooks which don't
+// set one.
+const DefaultMessageTemplate = `{ "message": "{{ jsonescape .ProjectName }} {{ jsonescape .Tag }} is out! Check it out at {{ jsonescape .GitURL }}/releases/tag/{{ jsonescape .Tag }}"}`
+
+type Pipe struct{}
+
+func (Pipe) String() string { return "webhook" }
+
+func (Pipe) Default(ctx *context.Context) error {
+	for i := range ctx.Config.Announce.Webhook {
+		webhook := &ctx.Config.Announce.Webhook[i]
+		if webhook.Name == "" {
+			webhook.Name = fmt.Sprintf("#%d", i+1)
+		}
+		if webhook.Method == "" {
+			webhook.Method = http.MethodPost
+		}
+		if webhook.ContentType == "" {
+			webhook.ContentType = defaultContentType
+		}
+		if webhook.MessageTemplate == "" {
+			webhook.MessageTemplate = DefaultMessageTemplate
+		}
+	}
+	return nil
+}
+
+func (Pipe) Announce(ctx *context.Context) error {
+	if ctx.SkipAnnounce {
+		return pipe.ErrSkipAnnounceEnabled
+	}
+	if len(ctx.Config.Announce.Webhook) == 0 {
+		return pipe.ErrSkipDisabledPipe
+	}
+
+	for _, webhook := range ctx.Config.Announce.Webhook {
+		if err := announce(ctx, webhook); err != nil {
+			return fmt.Errorf("announce: failed to announce to webhook %s: %w", webhook.Name, err)
+		}
+	}
+	return nil
+}
+
+func announce(ctx *context.Context, webhook config.Webhook) error {
+	if webhook.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+
+	t := tmpl.New(ctx)
+	url, err := t.Apply(webhook.URL)
+	if err != nil {
+		return err
+	}
+	body, err := t.Apply(webhook.MessageTemplate)
+	if err != nil {
+		return err
+	}
+
+	headers := map[string]string{}
+	for name, value := range webhook.Headers {
+		// headers usually hold secrets, which should be read from the
+		// environment through templates.
+		value, err := t.Apply(value)
+		if err != nil {
+			return fmt.Errorf("failed to template header %s: %w", name, err)
+		}
+		headers[name] = value
+	}
+
+	log.WithField("webhook", webhook.Name).Infof("posting: '%s'", body)
+	return announcer.Send(ctx, webhook.Method, url, webhook.ContentType, []byte(body), headers)
+}
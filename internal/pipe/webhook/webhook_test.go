@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goreleaser/goreleaser/internal/testlib"
+	"github.com/goreleaser/goreleaser/pkg/config"
+	"github.com/goreleaser/goreleaser/pkg/context"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringer(t *testing.T) {
+	require.Equal(t, Pipe{}.String(), "webhook")
+}
+
+func TestDefault(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Webhook: []config.Webhook{
+				{URL: "https://example.com"},
+				{Name: "teams", URL: "https://example.com", Method: http.MethodPut, ContentType: "text/plain", MessageTemplate: "hi"},
+			},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.Equal(t, []config.Webhook{
+		{
+			Name:            "#1",
+			URL:             "https://example.com",
+			Method:          http.MethodPost,
+			ContentType:     defaultContentType,
+			MessageTemplate: DefaultMessageTemplate,
+		},
+		{
+			Name:            "teams",
+			URL:             "https://example.com",
+			Method:          http.MethodPut,
+			ContentType:     "text/plain",
+			MessageTemplate: "hi",
+		},
+	}, ctx.Config.Announce.Webhook)
+}
+
+func TestAnnounceDisabled(t *testing.T) {
+	ctx := context.New(config.Project{})
+	require.NoError(t, Pipe{}.Default(ctx))
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceSkipAnnounce(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Webhook: []config.Webhook{{URL: "https://example.com"}},
+		},
+	})
+	ctx.SkipAnnounce = true
+	testlib.AssertSkipped(t, Pipe{}.Announce(ctx))
+}
+
+func TestAnnounceMissingURL(t *testing.T) {
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Webhook: []config.Webhook{{}},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to webhook #1: url is required`)
+}
+
+func TestAnnounceInvalidTemplates(t *testing.T) {
+	for name, webhook := range map[string]config.Webhook{
+		"url":     {URL: "{{ .Foo }"},
+		"message": {URL: "https://example.com", MessageTemplate: "{{ .Foo }"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.New(config.Project{
+				Announce: config.Announce{
+					Webhook: []config.Webhook{webhook},
+				},
+			})
+			require.NoError(t, Pipe{}.Default(ctx))
+			require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to webhook #1: template: tmpl:1: unexpected "}" in operand`)
+		})
+	}
+
+	t.Run("header", func(t *testing.T) {
+		ctx := context.New(config.Project{
+			Announce: config.Announce{
+				Webhook: []config.Webhook{{
+					URL:     "https://example.com",
+					Headers: map[string]string{"Authorization": "{{ .Env.NOPE }}"},
+				}},
+			},
+		})
+		require.NoError(t, Pipe{}.Default(ctx))
+		require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to webhook #1: failed to template header Authorization: template: tmpl:1:7: executing "tmpl" at <.Env.NOPE>: map has no entry for key "NOPE"`)
+	})
+}
+
+func TestAnnounce(t *testing.T) {
+	var method, path, contentType, auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		auth = r.Header.Get("Authorization")
+		bts, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		body = string(bts)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		ProjectName: "foo",
+		Announce: config.Announce{
+			Webhook: []config.Webhook{{
+				Name:    "deploy bot",
+				URL:     srv.URL + "/hooks/{{ .ProjectName }}",
+				Method:  http.MethodPut,
+				Headers: map[string]string{"Authorization": "Bearer {{ .Env.WEBHOOK_TOKEN }}"},
+			}},
+		},
+	})
+	ctx.Env["WEBHOOK_TOKEN"] = "secret"
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Git.URL = "https://github.com/foo/bar"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+	require.Equal(t, http.MethodPut, method)
+	require.Equal(t, "/hooks/foo", path)
+	require.Equal(t, defaultContentType, contentType)
+	require.Equal(t, "Bearer secret", auth)
+	require.Equal(t, `{ "message": "foo v1.0.0 is out! Check it out at https://github.com/foo/bar/releases/tag/v1.0.0"}`, body)
+}
+
+func TestAnnounceDefaultMessageIsValidJSON(t *testing.T) {
+	var body map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		ProjectName: `foo "bar" \baz`,
+		Announce: config.Announce{
+			Webhook: []config.Webhook{{URL: srv.URL}},
+		},
+	})
+	ctx.Git.CurrentTag = "v1.0.0"
+	ctx.Git.URL = "https://github.com/foo/bar"
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.NoError(t, Pipe{}.Announce(ctx))
+	require.Equal(t, map[string]string{
+		"message": `foo "bar" \baz v1.0.0 is out! Check it out at https://github.com/foo/bar/releases/tag/v1.0.0`,
+	}, body)
+}
+
+func TestAnnounceError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = io.WriteString(w, "invalid payload\n")
+	}))
+	defer srv.Close()
+
+	ctx := context.New(config.Project{
+		Announce: config.Announce{
+			Webhook: []config.Webhook{{Name: "teams", URL: srv.URL}},
+		},
+	})
+	require.NoError(t, Pipe{}.Default(ctx))
+	require.EqualError(t, Pipe{}.Announce(ctx), `announce: failed to announce to webhook teams: unexpected http status code 400: invalid payload`)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/goreleaser/goreleaser/internal/pipe/git"
 	"github.com/goreleaser/goreleaser/internal/pipe/nfpm"
 	"github.com/goreleaser/goreleaser/internal/pipe/publish"
+	"github.com/goreleaser/goreleaser/internal/pipe/repository"
 	"github.com/goreleaser/goreleaser/internal/pipe/sign"
 	"github.com/goreleaser/goreleaser/internal/pipe/snapcraft"
 	"github.com/goreleaser/goreleaser/internal/pipe/snapshot"
@@ -59,9 +60,11 @@ var Pipeline = append(
 	archive.Pipe{},       // archive in tar.gz, zip or binary (which does no archiving at all)
 	sourcearchive.Pipe{}, // archive the source code using git-archive
 	nfpm.Pipe{},          // archive via fpm (deb, rpm) using "native" go impl
+	nfpm.LintPipe{},      // validate the generated deb and rpm packages
 	snapcraft.Pipe{},     // archive via snapcraft (snap)
 	checksums.Pipe{},     // checksums of the files
 	sign.Pipe{},          // sign artifacts
+	repository.Pipe{},    // generate apt and yum repositories
 	docker.Pipe{},        // create and push docker images
 	publish.Pipe{},       // publishes artifacts
 	announce.Pipe{},      // announce releases
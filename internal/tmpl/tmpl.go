@@ -3,13 +3,16 @@ package tmpl
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	htmltemplate "html/template"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	"github.com/goreleaser/goreleaser/internal/artifact"
 	"github.com/goreleaser/goreleaser/pkg/build"
 	"github.com/goreleaser/goreleaser/pkg/context"
@@ -155,18 +158,7 @@ func (t *Template) Apply(s string) (string, error) {
 	var out bytes.Buffer
 	tmpl, err := template.New("tmpl").
 		Option("missingkey=error").
-		Funcs(template.FuncMap{
-			"replace": strings.ReplaceAll,
-			"time": func(s string) string {
-				return time.Now().UTC().Format(s)
-			},
-			"tolower":    strings.ToLower,
-			"toupper":    strings.ToUpper,
-			"trim":       strings.TrimSpace,
-			"trimprefix": strings.TrimPrefix,
-			"dir":        filepath.Dir,
-			"abs":        filepath.Abs,
-		}).
+		Funcs(funcs()).
 		Parse(s)
 	if err != nil {
 		return "", err
@@ -176,6 +168,96 @@ func (t *Template) Apply(s string) (string, error) {
 	return out.String(), err
 }
 
+// ApplyHTML applies the given string against the Fields stored in the
+// template, escaping the values so they can be safely used in HTML pages.
+func (t *Template) ApplyHTML(s string) (string, error) {
+	var out bytes.Buffer
+	tmpl, err := htmltemplate.New("tmpl").
+		Option("missingkey=error").
+		Funcs(htmltemplate.FuncMap(funcs())).
+		Parse(s)
+	if err != nil {
+		return "", err
+	}
+
+	err = tmpl.Execute(&out, t.fields)
+	return out.String(), err
+}
+
+// funcs returns the functions available to the templates.
+func funcs() template.FuncMap {
+	return template.FuncMap{
+		"replace": strings.ReplaceAll,
+		"time": func(s string) string {
+			return time.Now().UTC().Format(s)
+		},
+		"tolower":    strings.ToLower,
+		"toupper":    strings.ToUpper,
+		"trim":       strings.TrimSpace,
+		"trimprefix": strings.TrimPrefix,
+		"dir":        filepath.Dir,
+		"abs":        filepath.Abs,
+		"mdv2escape": mdv2Escape,
+		"jsonescape": jsonEscape,
+		"incmajor":   incVersion(semver.Version.IncMajor),
+		"incminor":   incVersion(semver.Version.IncMinor),
+		"incpatch":   incVersion(semver.Version.IncPatch),
+	}
+}
+
+// incVersion returns a function which increments the given semver with inc,
+// keeping its "v" prefix, if any.
+func incVersion(inc func(semver.Version) semver.Version) func(string) (string, error) {
+	return func(v string) (string, error) {
+		sv, err := semver.NewVersion(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse %q as semver: %w", v, err)
+		}
+		var prefix string
+		if strings.HasPrefix(v, "v") {
+			prefix = "v"
+		}
+		return prefix + inc(*sv).String(), nil
+	}
+}
+
+// jsonEscape escapes the given string so it can be used inside a JSON string.
+func jsonEscape(s string) (string, error) {
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return "", err
+	}
+	escaped := strings.TrimSpace(out.String())
+	return escaped[1 : len(escaped)-1], nil
+}
+
+// mdv2Escape escapes the characters reserved by Telegram's MarkdownV2.
+func mdv2Escape(s string) string {
+	return strings.NewReplacer(
+		"\\", "\\\\",
+		"_", "\\_",
+		"*", "\\*",
+		"[", "\\[",
+		"]", "\\]",
+		"(", "\\(",
+		")", "\\)",
+		"~", "\\~",
+		"`", "\\`",
+		">", "\\>",
+		"#", "\\#",
+		"+", "\\+",
+		"-", "\\-",
+		"=", "\\=",
+		"|", "\\|",
+		"{", "\\{",
+		"}", "\\}",
+		".", "\\.",
+		"!", "\\!",
+	).Replace(s)
+}
+
 type ExpectedSingleEnvErr struct{}
 
 func (e ExpectedSingleEnvErr) Error() string {
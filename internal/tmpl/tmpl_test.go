@@ -205,6 +205,31 @@ func TestFuncMap(t *testing.T) {
 			Name:     "abs",
 			Expected: filepath.Join(wd, "file"),
 		},
+		{
+			Template: `{{ mdv2escape "aaa_v1.2.3-beta (rc)!" }}`,
+			Name:     "mdv2escape",
+			Expected: `aaa\_v1\.2\.3\-beta \(rc\)\!`,
+		},
+		{
+			Template: `{{ jsonescape "a \"quoted\" <b>\\path\n" }}`,
+			Name:     "jsonescape",
+			Expected: `a \"quoted\" <b>\\path\n`,
+		},
+		{
+			Template: `{{ incmajor "v1.2.4" }}`,
+			Name:     "incmajor",
+			Expected: "v2.0.0",
+		},
+		{
+			Template: `{{ incminor "1.2.4" }}`,
+			Name:     "incminor",
+			Expected: "1.3.0",
+		},
+		{
+			Template: `{{ incpatch .Tag }}`,
+			Name:     "incpatch",
+			Expected: "v1.2.5",
+		},
 	} {
 		out, err := New(ctx).Apply(tc.Template)
 		require.NoError(t, err)
@@ -216,6 +241,11 @@ func TestFuncMap(t *testing.T) {
 	}
 }
 
+func TestIncVersionInvalid(t *testing.T) {
+	_, err := New(context.New(config.Project{})).Apply(`{{ incpatch "nope" }}`)
+	require.EqualError(t, err, `template: tmpl:1:3: executing "tmpl" at <incpatch "nope">: error calling incpatch: failed to parse "nope" as semver: Invalid Semantic Version`)
+}
+
 func TestApplySingleEnvOnly(t *testing.T) {
 	ctx := context.New(config.Project{
 		Env: []string{
@@ -304,3 +334,15 @@ func TestWithExtraFields(t *testing.T) {
 	}).Apply("{{ .MyCustomField }}")
 	require.Equal(t, "foo", out)
 }
+
+func TestApplyHTML(t *testing.T) {
+	ctx := context.New(config.Project{ProjectName: "proj"})
+	out, err := New(ctx).WithExtraFields(Fields{
+		"Name": `<b>"foo"</b>.txt`,
+	}).ApplyHTML(`<a href="{{ .Name }}">{{ toupper .ProjectName }} {{ .Name }}</a>`)
+	require.NoError(t, err)
+	require.Equal(t, `<a href="%3cb%3e%22foo%22%3c/b%3e.txt">PROJ &lt;b&gt;&#34;foo&#34;&lt;/b&gt;.txt</a>`, out)
+
+	_, err = New(ctx).ApplyHTML("{{ .Nope }}")
+	require.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/apex/log"
 	"github.com/goreleaser/nfpm/v2/files"
@@ -289,14 +290,17 @@ type Release struct {
 	DiscussionCategoryName string      `yaml:"discussion_category_name,omitempty"`
 	Header                 string      `yaml:"header,omitempty"`
 	Footer                 string      `yaml:"footer,omitempty"`
+	Mode                   string      `yaml:"mode,omitempty"`
 }
 
 // Milestone config used for VCS milestone.
 type Milestone struct {
-	Repo         Repo   `yaml:",omitempty"`
-	Close        bool   `yaml:",omitempty"`
-	FailOnError  bool   `yaml:"fail_on_error,omitempty"`
-	NameTemplate string `yaml:"name_template,omitempty"`
+	Repo             Repo   `yaml:",omitempty"`
+	Close            bool   `yaml:",omitempty"`
+	FailOnError      bool   `yaml:"fail_on_error,omitempty"`
+	NameTemplate     string `yaml:"name_template,omitempty"`
+	NextNameTemplate string `yaml:"next_name_template,omitempty"`
+	MoveOpenIssues   bool   `yaml:"move_open_issues,omitempty"`
 }
 
 // ExtraFile on a release.
@@ -320,7 +324,12 @@ type NFPM struct {
 	Description string   `yaml:",omitempty"`
 	License     string   `yaml:",omitempty"`
 	Bindir      string   `yaml:",omitempty"`
+	Changelog   string   `yaml:",omitempty"`
+	Lint        bool     `yaml:",omitempty"`
 	Meta        bool     `yaml:",omitempty"` // make package without binaries - only deps
+
+	ChangelogDistribution string `yaml:"changelog_distribution,omitempty"`
+	ChangelogUrgency      string `yaml:"changelog_urgency,omitempty"`
 }
 
 // NFPMScripts is used to specify maintainer scripts.
@@ -477,7 +486,8 @@ type Snapcraft struct {
 	Apps        map[string]SnapcraftAppMetadata    `yaml:",omitempty"`
 	Plugs       map[string]interface{}             `yaml:",omitempty"`
 
-	Files []SnapcraftExtraFiles `yaml:"extra_files,omitempty"`
+	ChannelTemplates []string              `yaml:"channel_templates,omitempty"`
+	Files            []SnapcraftExtraFiles `yaml:"extra_files,omitempty"`
 }
 
 // SnapcraftExtraFiles config.
@@ -502,20 +512,26 @@ type Checksum struct {
 
 // Docker image config.
 type Docker struct {
-	Binaries           []string `yaml:",omitempty"` // deprecated: no need to use this anymore
-	Builds             []string `yaml:",omitempty"` // deprecated: use IDs instead
-	IDs                []string `yaml:"ids,omitempty"`
-	Goos               string   `yaml:",omitempty"`
-	Goarch             string   `yaml:",omitempty"`
-	Goarm              string   `yaml:",omitempty"`
-	Dockerfile         string   `yaml:",omitempty"`
-	ImageTemplates     []string `yaml:"image_templates,omitempty"`
-	SkipPush           string   `yaml:"skip_push,omitempty"`
-	Files              []string `yaml:"extra_files,omitempty"`
-	BuildFlagTemplates []string `yaml:"build_flag_templates,omitempty"`
-	PushFlags          []string `yaml:"push_flags,omitempty"`
-	Buildx             bool     `yaml:"use_buildx,omitempty"` // deprecated: use Use instead
-	Use                string   `yaml:"use,omitempty"`
+	Binaries            []string `yaml:",omitempty"` // deprecated: no need to use this anymore
+	Builds              []string `yaml:",omitempty"` // deprecated: use IDs instead
+	IDs                 []string `yaml:"ids,omitempty"`
+	Goos                string   `yaml:",omitempty"`
+	Goarch              string   `yaml:",omitempty"`
+	Goarm               string   `yaml:",omitempty"`
+	Dockerfile          string   `yaml:",omitempty"`
+	ImageTemplates      []string `yaml:"image_templates,omitempty"`
+	SkipPush            string   `yaml:"skip_push,omitempty"`
+	Files               []string `yaml:"extra_files,omitempty"`
+	BuildFlagTemplates  []string `yaml:"build_flag_templates,omitempty"`
+	PushFlags           []string `yaml:"push_flags,omitempty"`
+	Buildx              bool     `yaml:"use_buildx,omitempty"` // deprecated: use Use instead
+	Use                 string   `yaml:"use,omitempty"`
+	OCILabels           bool     `yaml:"oci_labels,omitempty"`
+	TemplatedDockerfile bool     `yaml:"templated_dockerfile,omitempty"`
+	TemplatedExtraFiles bool     `yaml:"templated_extra_files,omitempty"`
+	ManifestTemplate    string   `yaml:"manifest_template,omitempty"`
+	ManifestCreateFlags []string `yaml:"manifest_create_flags,omitempty"`
+	ManifestPushFlags   []string `yaml:"manifest_push_flags,omitempty"`
 }
 
 // DockerManifest config.
@@ -530,14 +546,26 @@ type DockerManifest struct {
 
 // Filters config.
 type Filters struct {
+	Include []string `yaml:",omitempty"`
 	Exclude []string `yaml:",omitempty"`
 }
 
 // Changelog Config.
 type Changelog struct {
-	Filters Filters `yaml:",omitempty"`
-	Sort    string  `yaml:",omitempty"`
-	Skip    bool    `yaml:",omitempty"`
+	Filters Filters          `yaml:",omitempty"`
+	Sort    string           `yaml:",omitempty"`
+	Skip    bool             `yaml:",omitempty"`
+	Use     string           `yaml:",omitempty"`
+	Groups  []ChangelogGroup `yaml:",omitempty"`
+}
+
+// ChangelogGroup groups the changelog entries under a title.
+type ChangelogGroup struct {
+	Title       string   `yaml:",omitempty"`
+	Labels      []string `yaml:",omitempty"`
+	Regexp      string   `yaml:",omitempty"`
+	Order       int      `yaml:",omitempty"`
+	StripPrefix bool     `yaml:"strip_prefix,omitempty"`
 }
 
 // EnvFiles holds paths to files that contains environment variables
@@ -555,21 +583,67 @@ type Before struct {
 
 // Blob contains config for GO CDK blob.
 type Blob struct {
-	Bucket     string      `yaml:",omitempty"`
-	Provider   string      `yaml:",omitempty"`
-	Region     string      `yaml:",omitempty"`
-	DisableSSL bool        `yaml:"disableSSL,omitempty"`
-	Folder     string      `yaml:",omitempty"`
-	KMSKey     string      `yaml:",omitempty"`
-	IDs        []string    `yaml:"ids,omitempty"`
-	Endpoint   string      `yaml:",omitempty"` // used for minio for example
-	ExtraFiles []ExtraFile `yaml:"extra_files,omitempty"`
+	Bucket       string      `yaml:",omitempty"`
+	Provider     string      `yaml:",omitempty"`
+	Region       string      `yaml:",omitempty"`
+	DisableSSL   bool        `yaml:"disableSSL,omitempty"`
+	Folder       string      `yaml:",omitempty"`
+	KMSKey       string      `yaml:",omitempty"`
+	IDs          []string    `yaml:"ids,omitempty"`
+	Repositories []string    `yaml:"repositories,omitempty"`
+	Endpoint     string      `yaml:",omitempty"` // used for minio for example
+	ExtraFiles   []ExtraFile `yaml:"extra_files,omitempty"`
+
+	ContentType        string `yaml:"content_type,omitempty"`
+	CacheControl       string `yaml:"cache_control,omitempty"`
+	ContentDisposition string `yaml:"content_disposition,omitempty"`
+	ACL                string `yaml:"acl,omitempty"`
+
+	Index  BlobIndex  `yaml:"index,omitempty"`
+	Latest BlobLatest `yaml:"latest,omitempty"`
+}
+
+// BlobIndex config.
+type BlobIndex struct {
+	Enabled      bool   `yaml:"enabled,omitempty"`
+	HTMLTemplate string `yaml:"html_template,omitempty"`
+	JSONTemplate string `yaml:"json_template,omitempty"`
+}
+
+// BlobLatest config.
+type BlobLatest struct {
+	Folder string `yaml:"folder,omitempty"`
+	File   string `yaml:"file,omitempty"`
+}
+
+// Repository config.
+type Repository struct {
+	ID        string              `yaml:"id,omitempty"`
+	IDs       []string            `yaml:"ids,omitempty"`
+	Existing  string              `yaml:"existing,omitempty"`
+	APT       APTRepository       `yaml:"apt,omitempty"`
+	Signature RepositorySignature `yaml:"signature,omitempty"`
+}
+
+// APTRepository config.
+type APTRepository struct {
+	Suite       string `yaml:",omitempty"`
+	Component   string `yaml:",omitempty"`
+	Origin      string `yaml:",omitempty"`
+	Label       string `yaml:",omitempty"`
+	Description string `yaml:",omitempty"`
+}
+
+// RepositorySignature config.
+type RepositorySignature struct {
+	KeyFile string `yaml:"key_file,omitempty"`
 }
 
 // Upload configuration.
 type Upload struct {
 	Name               string            `yaml:",omitempty"`
 	IDs                []string          `yaml:"ids,omitempty"`
+	Repositories       []string          `yaml:"repositories,omitempty"`
 	Target             string            `yaml:",omitempty"`
 	Username           string            `yaml:",omitempty"`
 	Mode               string            `yaml:",omitempty"`
@@ -580,6 +654,28 @@ type Upload struct {
 	Signature          bool              `yaml:",omitempty"`
 	CustomArtifactName bool              `yaml:"custom_artifact_name,omitempty"`
 	CustomHeaders      map[string]string `yaml:"custom_headers,omitempty"`
+	BodyFormat         string            `yaml:"body_format,omitempty"`
+	FileField          string            `yaml:"file_field,omitempty"`
+	FormFields         map[string]string `yaml:"form_fields,omitempty"`
+	Auth               UploadAuth        `yaml:"auth,omitempty"`
+	Chunked            UploadChunked     `yaml:"chunked,omitempty"`
+}
+
+// UploadChunked config.
+type UploadChunked struct {
+	Enabled   bool          `yaml:"enabled,omitempty"`
+	ChunkSize int64         `yaml:"chunk_size,omitempty"`
+	Retries   int           `yaml:"retries,omitempty"`
+	Backoff   time.Duration `yaml:"backoff,omitempty"`
+}
+
+// UploadAuth config.
+type UploadAuth struct {
+	Type       string   `yaml:"type,omitempty"`
+	TokenURL   string   `yaml:"token_url,omitempty"`
+	Scopes     []string `yaml:"scopes,omitempty"`
+	ClientCert string   `yaml:"client_cert,omitempty"`
+	ClientKey  string   `yaml:"client_key,omitempty"`
 }
 
 // Publisher configuration.
@@ -619,6 +715,7 @@ type Project struct {
 	Artifactories   []Upload         `yaml:",omitempty"`
 	Uploads         []Upload         `yaml:",omitempty"`
 	Blobs           []Blob           `yaml:"blobs,omitempty"`
+	Repositories    []Repository     `yaml:"repositories,omitempty"`
 	Publishers      []Publisher      `yaml:"publishers,omitempty"`
 	Changelog       Changelog        `yaml:",omitempty"`
 	Dist            string           `yaml:",omitempty"`
@@ -649,7 +746,20 @@ type GoMod struct {
 }
 
 type Announce struct {
-	Twitter Twitter `yaml:"twitter,omitempty"`
+	Twitter   Twitter           `yaml:"twitter,omitempty"`
+	Slack     Slack             `yaml:"slack,omitempty"`
+	Discord   Discord           `yaml:"discord,omitempty"`
+	Webhook   []Webhook         `yaml:"webhook,omitempty"`
+	SMTP      SMTP              `yaml:"smtp,omitempty"`
+	Mastodon  Mastodon          `yaml:"mastodon,omitempty"`
+	Telegram  Telegram          `yaml:"telegram,omitempty"`
+	OnFailure AnnounceOnFailure `yaml:"on_failure,omitempty"`
+}
+
+type AnnounceOnFailure struct {
+	Enabled         bool     `yaml:"enabled,omitempty"`
+	Announcers      []string `yaml:"announcers,omitempty"`
+	MessageTemplate string   `yaml:"message_template,omitempty"`
 }
 
 type Twitter struct {
@@ -657,6 +767,61 @@ type Twitter struct {
 	MessageTemplate string `yaml:"message_template,omitempty"`
 }
 
+type Slack struct {
+	Enabled         bool          `yaml:"enabled,omitempty"`
+	MessageTemplate string        `yaml:"message_template,omitempty"`
+	Channel         string        `yaml:"channel,omitempty"`
+	Username        string        `yaml:"username,omitempty"`
+	IconEmoji       string        `yaml:"icon_emoji,omitempty"`
+	IconURL         string        `yaml:"icon_url,omitempty"`
+	Blocks          []interface{} `yaml:"blocks,omitempty"`
+}
+
+type Discord struct {
+	Enabled         bool   `yaml:"enabled,omitempty"`
+	MessageTemplate string `yaml:"message_template,omitempty"`
+	Author          string `yaml:"author,omitempty"`
+	Color           string `yaml:"color,omitempty"`
+	IconURL         string `yaml:"icon_url,omitempty"`
+}
+
+type Webhook struct {
+	Name            string            `yaml:"name,omitempty"`
+	URL             string            `yaml:"url,omitempty"`
+	Method          string            `yaml:"method,omitempty"`
+	Headers         map[string]string `yaml:"headers,omitempty"`
+	ContentType     string            `yaml:"content_type,omitempty"`
+	MessageTemplate string            `yaml:"message_template,omitempty"`
+	FailureTemplate string            `yaml:"failure_template,omitempty"`
+}
+
+type SMTP struct {
+	Enabled            bool     `yaml:"enabled,omitempty"`
+	Host               string   `yaml:"host,omitempty"`
+	Port               int      `yaml:"port,omitempty"`
+	TLS                string   `yaml:"tls,omitempty"`
+	InsecureSkipVerify bool     `yaml:"insecure_skip_verify,omitempty"`
+	Username           string   `yaml:"username,omitempty"`
+	From               string   `yaml:"from,omitempty"`
+	To                 []string `yaml:"to,omitempty"`
+	CC                 []string `yaml:"cc,omitempty"`
+	SubjectTemplate    string   `yaml:"subject_template,omitempty"`
+	BodyTemplate       string   `yaml:"body_template,omitempty"`
+	BodyFormat         string   `yaml:"body_format,omitempty"`
+}
+
+type Mastodon struct {
+	Enabled         bool   `yaml:"enabled,omitempty"`
+	MessageTemplate string `yaml:"message_template,omitempty"`
+	Server          string `yaml:"server,omitempty"`
+}
+
+type Telegram struct {
+	Enabled         bool   `yaml:"enabled,omitempty"`
+	MessageTemplate string `yaml:"message_template,omitempty"`
+	ChatID          string `yaml:"chat_id,omitempty"`
+}
+
 // Load config file.
 func Load(file string) (config Project, err error) {
 	f, err := os.Open(file) // #nosec
@@ -73,6 +73,7 @@ type Context struct {
 	Date               time.Time
 	Artifacts          artifact.Artifacts
 	ReleaseNotes       string
+	ChangelogEntries   []string
 	ReleaseNotesFile   string
 	ReleaseNotesTmpl   string
 	ReleaseHeaderFile  string